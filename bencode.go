@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// Bencode (https://en.wikipedia.org/wiki/Bencode) is the wire format of nREPL.
+// Decoded values are strings, int64s, []interface{} and map[string]interface{}.
+
+const (
+	// Strings are read into memory whole, so their length is limited.
+	bencodeMaxStringLength = 64 << 20
+	bencodeMaxDepth        = 100
+)
+
+func bencodeRead(r *bufio.Reader) (interface{}, error) {
+	return bencodeReadNested(r, 0)
+}
+
+// bencodeReadNested reads a value nested in depth lists and dictionaries.
+func bencodeReadNested(r *bufio.Reader, depth int) (interface{}, error) {
+	if depth > bencodeMaxDepth {
+		return nil, fmt.Errorf("bencode: lists and dictionaries nested deeper than %d", bencodeMaxDepth)
+	}
+	c, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case c == 'i':
+		s, err := r.ReadString('e')
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		return strconv.ParseInt(s[:len(s)-1], 10, 64)
+	case c == 'l':
+		res := []interface{}{}
+		for {
+			if next, err := r.Peek(1); err != nil {
+				return nil, unexpectedEOF(err)
+			} else if next[0] == 'e' {
+				r.ReadByte()
+				return res, nil
+			}
+			v, err := bencodeReadNested(r, depth+1)
+			if err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			res = append(res, v)
+		}
+	case c == 'd':
+		res := map[string]interface{}{}
+		for {
+			if next, err := r.Peek(1); err != nil {
+				return nil, unexpectedEOF(err)
+			} else if next[0] == 'e' {
+				r.ReadByte()
+				return res, nil
+			}
+			k, err := bencodeReadNested(r, depth+1)
+			if err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("bencode: dictionary key must be a string, got %v", k)
+			}
+			v, err := bencodeReadNested(r, depth+1)
+			if err != nil {
+				return nil, unexpectedEOF(err)
+			}
+			res[key] = v
+		}
+	case c >= '0' && c <= '9':
+		r.UnreadByte()
+		s, err := r.ReadString(':')
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil || n < 0 || n > bencodeMaxStringLength {
+			return nil, fmt.Errorf("bencode: invalid string length %q", s[:len(s)-1])
+		}
+		b := make([]byte, n)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		return string(b), nil
+	default:
+		return nil, fmt.Errorf("bencode: unexpected character %q", c)
+	}
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func bencodeWrite(w io.Writer, v interface{}) error {
+	var err error
+	switch v := v.(type) {
+	case string:
+		_, err = fmt.Fprintf(w, "%d:%s", len(v), v)
+	case int:
+		_, err = fmt.Fprintf(w, "i%de", v)
+	case int64:
+		_, err = fmt.Fprintf(w, "i%de", v)
+	case []string:
+		items := make([]interface{}, len(v))
+		for i, s := range v {
+			items[i] = s
+		}
+		err = bencodeWrite(w, items)
+	case []interface{}:
+		if _, err = io.WriteString(w, "l"); err != nil {
+			return err
+		}
+		for _, item := range v {
+			if err = bencodeWrite(w, item); err != nil {
+				return err
+			}
+		}
+		_, err = io.WriteString(w, "e")
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		if _, err = io.WriteString(w, "d"); err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if err = bencodeWrite(w, k); err != nil {
+				return err
+			}
+			if err = bencodeWrite(w, v[k]); err != nil {
+				return err
+			}
+		}
+		_, err = io.WriteString(w, "e")
+	default:
+		err = fmt.Errorf("bencode: cannot encode %T", v)
+	}
+	return err
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBencodeRoundTrip(t *testing.T) {
+	msg := map[string]interface{}{
+		"op":     "eval",
+		"code":   "(str \"λ\" 1)",
+		"id":     int64(42),
+		"status": []interface{}{"done", []interface{}{}},
+		"nested": map[string]interface{}{},
+	}
+	var b bytes.Buffer
+	if err := bencodeWrite(&b, msg); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(b.String(), "d4:code12:(str \"λ\" 1)2:idi42e") {
+		t.Fatalf("unexpected encoding %q", b.String())
+	}
+	res, err := bencodeRead(bufio.NewReader(&b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(res, msg) {
+		t.Fatalf("expected %v, got %v", msg, res)
+	}
+	if _, err := bencodeRead(bufio.NewReader(strings.NewReader("d2:op"))); err == nil {
+		t.Fatal("expected an error for truncated input")
+	}
+}
+
+func TestBencodeLimits(t *testing.T) {
+	for _, s := range []string{"99999999999999:", "67108865:"} {
+		if _, err := bencodeRead(bufio.NewReader(strings.NewReader(s))); err == nil || !strings.Contains(err.Error(), "invalid string length") {
+			t.Errorf("expected an invalid string length error for %q, got %v", s, err)
+		}
+	}
+	deep := strings.Repeat("l", bencodeMaxDepth+2) + strings.Repeat("e", bencodeMaxDepth+2)
+	if _, err := bencodeRead(bufio.NewReader(strings.NewReader(deep))); err == nil || !strings.Contains(err.Error(), "nested deeper") {
+		t.Errorf("expected a nesting error, got %v", err)
+	}
+	ok := strings.Repeat("l", bencodeMaxDepth+1) + strings.Repeat("e", bencodeMaxDepth+1)
+	if _, err := bencodeRead(bufio.NewReader(strings.NewReader(ok))); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,254 @@
+package core
+
+import (
+	"sync"
+	"time"
+	"unsafe"
+)
+
+type (
+	agentAction struct {
+		fn   Callable
+		args []Object
+		// done is closed instead of calling fn, used by await.
+		done chan struct{}
+		// exitSession is EXIT_SESSION where the action was sent.
+		exitSession func()
+	}
+	// Agent holds a value that is changed by actions run
+	// asynchronously, one at a time, in the order they were sent.
+	Agent struct {
+		MetaHolder
+		WatchHolder
+		value        Object
+		queue        []agentAction
+		running      bool
+		err          Error
+		errorHandler Callable
+		failOnError  bool
+	}
+)
+
+var (
+	runningAgents  int
+	agentsIdle     = sync.NewCond(&RT.GIL)
+	agentsShutdown bool
+)
+
+func NewAgent(value Object) *Agent {
+	return &Agent{value: value, failOnError: true}
+}
+
+func (a *Agent) ToString(escape bool) string {
+	status := ":ready"
+	if a.err != nil {
+		status = ":failed"
+	}
+	return "#object[Agent {:status " + status + ", :val " + a.value.ToString(escape) + "}]"
+}
+
+func (a *Agent) Equals(other interface{}) bool {
+	return a == other
+}
+
+func (a *Agent) GetInfo() *ObjectInfo {
+	return nil
+}
+
+func (a *Agent) GetType() *Type {
+	return TYPE.Agent
+}
+
+func (a *Agent) Hash() uint32 {
+	return HashPtr(uintptr(unsafe.Pointer(a)))
+}
+
+func (a *Agent) WithInfo(info *ObjectInfo) Object {
+	return a
+}
+
+func (a *Agent) WithMeta(meta Map) Object {
+	res := *a
+	res.meta = SafeMerge(res.meta, meta)
+	return &res
+}
+
+func (a *Agent) ResetMeta(newMeta Map) Map {
+	a.meta = newMeta
+	return a.meta
+}
+
+func (a *Agent) AlterMeta(fn *Fn, args []Object) Map {
+	return AlterMeta(&a.MetaHolder, fn, args)
+}
+
+func (a *Agent) Deref() Object {
+	return a.value
+}
+
+func (a *Agent) Error() Error {
+	return a.err
+}
+
+func (a *Agent) SetErrorHandler(handler Callable) {
+	a.errorHandler = handler
+}
+
+func (a *Agent) ErrorHandler() Callable {
+	return a.errorHandler
+}
+
+func (a *Agent) SetFailOnError(fail bool) {
+	a.failOnError = fail
+}
+
+func (a *Agent) FailOnError() bool {
+	return a.failOnError
+}
+
+// Send queues an action that will set the value of the agent to
+// (apply fn value args). All agent functions must be called with the GIL held.
+func (a *Agent) Send(fn Callable, args []Object) {
+	a.dispatch(agentAction{fn: fn, args: args, exitSession: EXIT_SESSION})
+}
+
+func (a *Agent) dispatch(action agentAction) {
+	if agentsShutdown {
+		panic(RT.NewError("Agents are shut down"))
+	}
+	if a.err != nil {
+		panic(RT.NewError("Agent is failed, needs restart: " + a.err.Message().ToString(false)))
+	}
+	a.queue = append(a.queue, action)
+	a.start()
+}
+
+func (a *Agent) start() {
+	if a.running || a.err != nil || len(a.queue) == 0 {
+		return
+	}
+	a.running = true
+	runningAgents++
+	go a.run()
+}
+
+func (a *Agent) run() {
+	RT.GIL.Lock()
+	defer RT.GIL.Unlock()
+	for {
+		if a.err != nil || len(a.queue) == 0 {
+			a.running = false
+			runningAgents--
+			if runningAgents == 0 {
+				agentsIdle.Broadcast()
+			}
+			return
+		}
+		action := a.queue[0]
+		a.queue = a.queue[1:]
+		a.execute(action)
+		// Give other goroutines a chance to run between actions.
+		RT.GIL.Unlock()
+		RT.GIL.Lock()
+	}
+}
+
+func (a *Agent) execute(action agentAction) {
+	if action.done != nil {
+		close(action.done)
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			switch r := r.(type) {
+			case Error:
+				a.handleError(r)
+			case SessionExit:
+				a.handleError(endSession(action.exitSession))
+			default:
+				panic(r)
+			}
+		}
+	}()
+	EXIT_SESSION = action.exitSession
+	a.setValue(a, &a.value, action.fn.Call(append([]Object{a.value}, action.args...)))
+}
+
+func (a *Agent) handleError(err Error) {
+	if a.failOnError {
+		a.err = err
+	}
+	if a.errorHandler != nil {
+		// Exceptions thrown by the error handler are ignored.
+		defer func() {
+			if r := recover(); r != nil {
+				if _, ok := r.(Error); !ok {
+					panic(r)
+				}
+			}
+		}()
+		a.errorHandler.Call([]Object{a, err})
+	}
+}
+
+// Restart clears the error of a failed agent, sets its value
+// and resumes processing of the queued actions, unless clearActions is set,
+// in which case they are discarded.
+func (a *Agent) Restart(value Object, clearActions bool) {
+	if a.err == nil {
+		panic(RT.NewError("Agent does not need a restart"))
+	}
+	a.validate(value)
+	a.value = value
+	a.err = nil
+	if clearActions {
+		a.queue = nil
+	}
+	a.start()
+}
+
+// Await returns a channel that will be closed once all actions
+// sent to the agent so far have been executed.
+func (a *Agent) Await() chan struct{} {
+	done := make(chan struct{})
+	a.dispatch(agentAction{done: done})
+	return done
+}
+
+// AwaitAgents waits for the agents to execute all actions sent to them so far,
+// releasing the GIL while blocked. A negative timeout means wait forever.
+// Returns false on timeout.
+func AwaitAgents(agents []*Agent, timeout time.Duration) bool {
+	dones := make([]chan struct{}, len(agents))
+	for i, a := range agents {
+		dones[i] = a.Await()
+	}
+	deadline := time.Now().Add(timeout)
+	for _, done := range dones {
+		remaining := timeout
+		if timeout >= 0 {
+			remaining = time.Until(deadline)
+			if remaining < 0 {
+				remaining = 0
+			}
+		}
+		if !waitReleasingGIL(done, remaining) {
+			return false
+		}
+	}
+	return true
+}
+
+// ShutdownAgents makes agents reject new actions.
+// Actions that are already queued will still run.
+func ShutdownAgents() {
+	agentsShutdown = true
+}
+
+// WaitForAgents waits, releasing the GIL, until no agent has actions
+// left to run. Must be called with the GIL held.
+func WaitForAgents() {
+	for runningAgents > 0 {
+		agentsIdle.Wait()
+	}
+}
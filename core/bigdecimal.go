@@ -0,0 +1,580 @@
+package core
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+type (
+	// BigDecimal is an arbitrary-precision decimal number,
+	// whose value is unscaled * 10^-scale.
+	BigDecimal struct {
+		InfoHolder
+		unscaled *big.Int
+		scale    int
+		Original string
+	}
+	RoundingMode int
+	// MathContext holds the precision (number of significant digits,
+	// 0 meaning unlimited) and the rounding mode used by BigDecimal operations.
+	MathContext struct {
+		Precision int
+		Rounding  RoundingMode
+	}
+	nativeBigDecimal struct {
+		d *BigDecimal
+	}
+)
+
+const (
+	ROUND_UP RoundingMode = iota
+	ROUND_DOWN
+	ROUND_CEILING
+	ROUND_FLOOR
+	ROUND_HALF_UP
+	ROUND_HALF_DOWN
+	ROUND_HALF_EVEN
+	ROUND_UNNECESSARY
+)
+
+var roundingModes = map[string]RoundingMode{
+	"UP":          ROUND_UP,
+	"DOWN":        ROUND_DOWN,
+	"CEILING":     ROUND_CEILING,
+	"FLOOR":       ROUND_FLOOR,
+	"HALF_UP":     ROUND_HALF_UP,
+	"HALF_DOWN":   ROUND_HALF_DOWN,
+	"HALF_EVEN":   ROUND_HALF_EVEN,
+	"UNNECESSARY": ROUND_UNNECESSARY,
+}
+
+var bigTen = big.NewInt(10)
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// numDigits returns the number of decimal digits in the absolute value of b.
+func numDigits(b *big.Int) int {
+	if b.Sign() == 0 {
+		return 1
+	}
+	s := b.String()
+	if s[0] == '-' {
+		return len(s) - 1
+	}
+	return len(s)
+}
+
+func MakeBigDecimal(unscaled *big.Int, scale int) *BigDecimal {
+	return &BigDecimal{unscaled: unscaled, scale: scale}
+}
+
+// Helper function that returns a BigDecimal given a string such as
+// "-1.10" or "1.5e-3", remembering any original string provided,
+// and true if the string had the proper format; nil and false otherwise.
+func MakeBigDecimalWithOrig(s, orig string) (*BigDecimal, bool) {
+	mantissa, exp := s, 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil {
+			return nil, false
+		}
+		mantissa, exp = s[:i], e
+	}
+	scale := 0
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		scale = len(mantissa) - i - 1
+		mantissa = mantissa[:i] + mantissa[i+1:]
+	}
+	digits := mantissa
+	if digits != "" && (digits[0] == '-' || digits[0] == '+') {
+		digits = digits[1:]
+	}
+	if digits == "" {
+		return nil, false
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return nil, false
+		}
+	}
+	u, ok := new(big.Int).SetString(mantissa, 10)
+	if !ok {
+		return nil, false
+	}
+	return &BigDecimal{unscaled: u, scale: scale - exp, Original: orig}, true
+}
+
+// toBigDecimal converts n to a BigDecimal. Ratios that have
+// no exact decimal representation are rounded according to
+// the current math context.
+func toBigDecimal(n Number) *BigDecimal {
+	switch n := n.(type) {
+	case *BigDecimal:
+		return n
+	case Int:
+		return &BigDecimal{unscaled: big.NewInt(int64(n.I))}
+	case *BigInt:
+		return &BigDecimal{unscaled: n.b}
+	case *Ratio:
+		return quoDecimal(n.r.Num(), n.r.Denom(), 0, CurrentMathContext())
+	case Double:
+		if math.IsInf(n.D, 0) || math.IsNaN(n.D) {
+			panic(RT.NewError("Cannot convert " + n.ToString(false) + " to BigDecimal"))
+		}
+		d, _ := MakeBigDecimalWithOrig(strconv.FormatFloat(n.D, 'g', -1, 64), "")
+		return d
+	case *BigFloat:
+		if n.b.IsInf() {
+			panic(RT.NewError("Cannot convert " + n.ToString(false) + " to BigDecimal"))
+		}
+		d, _ := MakeBigDecimalWithOrig(n.b.Text('g', -1), "")
+		return d
+	default:
+		panic(RT.NewError(fmt.Sprintf("Cannot convert %s (type: %s) to BigDecimal", n.ToString(true), n.GetType().ToString(false))))
+	}
+}
+
+// Text returns the decimal representation of d without the M suffix,
+// using scientific notation for very large and very small exponents.
+func (d *BigDecimal) Text() string {
+	coeff := new(big.Int).Abs(d.unscaled).String()
+	adjusted := len(coeff) - 1 - d.scale
+	var res string
+	switch {
+	case d.scale == 0:
+		res = coeff
+	case d.scale > 0 && adjusted >= -6:
+		if n := len(coeff) - d.scale; n > 0 {
+			res = coeff[:n] + "." + coeff[n:]
+		} else {
+			res = "0." + strings.Repeat("0", -n) + coeff
+		}
+	default:
+		res = coeff[:1]
+		if len(coeff) > 1 {
+			res += "." + coeff[1:]
+		}
+		res += "E"
+		if adjusted >= 0 {
+			res += "+"
+		}
+		res += strconv.Itoa(adjusted)
+	}
+	if d.unscaled.Sign() < 0 {
+		return "-" + res
+	}
+	return res
+}
+
+func (d *BigDecimal) ToString(escape bool) string {
+	if FORMAT_MODE && d.Original != "" {
+		return d.Original
+	}
+	return d.Text() + "M"
+}
+
+func (d *BigDecimal) Equals(other interface{}) bool {
+	return equalsNumbers(d, other)
+}
+
+func (d *BigDecimal) GetType() *Type {
+	return TYPE.BigDecimal
+}
+
+func (d *BigDecimal) Hash() uint32 {
+	// Numerically equal decimals must have the same hash
+	// regardless of their scale.
+	n := d.stripTrailingZeros(math.MinInt32)
+	h := getHash()
+	b, err := n.unscaled.GobEncode()
+	PanicOnErr(err)
+	h.Write(b)
+	s := make([]byte, 8)
+	binary.LittleEndian.PutUint64(s, uint64(n.scale))
+	h.Write(s)
+	return h.Sum32()
+}
+
+func (d *BigDecimal) Compare(other Object) int {
+	return CompareNumbers(d, EnsureObjectIsNumber(other, "Cannot compare BigDecimal: %s"))
+}
+
+func (d *BigDecimal) Scale() int {
+	return d.scale
+}
+
+func (d *BigDecimal) Unscaled() *big.Int {
+	return d.unscaled
+}
+
+// stripTrailingZeros removes trailing zeros from the unscaled value
+// as long as the scale stays >= minScale.
+func (d *BigDecimal) stripTrailingZeros(minScale int) *BigDecimal {
+	if d.unscaled.Sign() == 0 {
+		if minScale < 0 {
+			minScale = 0
+		}
+		return &BigDecimal{unscaled: d.unscaled, scale: minScale}
+	}
+	u, scale := d.unscaled, d.scale
+	q, r := new(big.Int), new(big.Int)
+	for scale > minScale {
+		q.QuoRem(u, bigTen, r)
+		if r.Sign() != 0 {
+			break
+		}
+		u, q = q, new(big.Int)
+		scale--
+	}
+	if scale == d.scale {
+		return d
+	}
+	return &BigDecimal{unscaled: u, scale: scale}
+}
+
+// withScale returns d rescaled to a larger scale.
+func (d *BigDecimal) withScale(scale int) *BigDecimal {
+	if scale <= d.scale {
+		return d
+	}
+	u := new(big.Int).Mul(d.unscaled, pow10(scale-d.scale))
+	return &BigDecimal{unscaled: u, scale: scale}
+}
+
+// Rounds d to the precision of mc. sticky means that the true value
+// has nonzero digits beyond those of d.
+func (d *BigDecimal) round(mc MathContext, sticky bool) *BigDecimal {
+	if mc.Precision <= 0 {
+		return d
+	}
+	abs := new(big.Int).Abs(d.unscaled)
+	drop := numDigits(abs) - mc.Precision
+	if drop <= 0 {
+		return d
+	}
+	neg := d.unscaled.Sign() < 0
+	divisor := pow10(drop)
+	q, r := new(big.Int).QuoRem(abs, divisor, new(big.Int))
+	if r.Sign() != 0 || sticky {
+		cmpHalf := new(big.Int).Lsh(r, 1).Cmp(divisor)
+		if cmpHalf == 0 && sticky {
+			cmpHalf = 1
+		}
+		if mc.Rounding.increments(q, cmpHalf, neg) {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	scale := d.scale - drop
+	if numDigits(q) > mc.Precision {
+		q.Quo(q, bigTen)
+		scale--
+	}
+	if neg {
+		q.Neg(q)
+	}
+	return &BigDecimal{unscaled: q, scale: scale}
+}
+
+// increments reports whether the magnitude of the truncated value q must be
+// incremented, given how the discarded fraction compares to one half.
+func (mode RoundingMode) increments(q *big.Int, cmpHalf int, neg bool) bool {
+	switch mode {
+	case ROUND_UP:
+		return true
+	case ROUND_DOWN:
+		return false
+	case ROUND_CEILING:
+		return !neg
+	case ROUND_FLOOR:
+		return neg
+	case ROUND_HALF_UP:
+		return cmpHalf >= 0
+	case ROUND_HALF_DOWN:
+		return cmpHalf > 0
+	case ROUND_HALF_EVEN:
+		return cmpHalf > 0 || cmpHalf == 0 && q.Bit(0) == 1
+	default:
+		panic(RT.NewError("Rounding necessary"))
+	}
+}
+
+func (mode RoundingMode) String() string {
+	for name, m := range roundingModes {
+		if m == mode {
+			return name
+		}
+	}
+	return "UNKNOWN"
+}
+
+func ParseRoundingMode(name string) (RoundingMode, bool) {
+	mode, ok := roundingModes[name]
+	return mode, ok
+}
+
+// CurrentMathContext returns the math context bound to *math-context*,
+// or the unlimited one if it's nil.
+func CurrentMathContext() MathContext {
+	m, ok := GLOBAL_ENV.mathContext.Value.(Map)
+	if !ok {
+		return MathContext{}
+	}
+	mc := MathContext{Rounding: ROUND_HALF_UP}
+	if ok, v := m.Get(MakeKeyword("precision")); ok {
+		mc.Precision = EnsureObjectIsInt(v, "*math-context* :precision: %s").I
+	}
+	if ok, v := m.Get(MakeKeyword("rounding")); ok {
+		mode, ok := ParseRoundingMode(EnsureObjectIsSymbol(v, "*math-context* :rounding: %s").ToString(false))
+		if !ok {
+			panic(RT.NewError("Unknown rounding mode: " + v.ToString(false)))
+		}
+		mc.Rounding = mode
+	}
+	return mc
+}
+
+// quoDecimal returns (num / den) * 10^-scale, with the given preferred scale.
+// Panics if the result has no exact decimal representation and
+// mc has unlimited precision.
+func quoDecimal(num, den *big.Int, scale int, mc MathContext) *BigDecimal {
+	if den.Sign() < 0 {
+		num, den = new(big.Int).Neg(num), new(big.Int).Neg(den)
+	}
+	g := new(big.Int).GCD(nil, nil, new(big.Int).Abs(num), den)
+	if g.Sign() != 0 {
+		num = new(big.Int).Quo(num, g)
+		den = new(big.Int).Quo(den, g)
+	}
+	// The expansion terminates iff den has no prime factors other than 2 and 5.
+	rest := new(big.Int).Set(den)
+	twos, fives := 0, 0
+	r := new(big.Int)
+	for rest.Bit(0) == 0 && rest.Sign() != 0 {
+		rest.Rsh(rest, 1)
+		twos++
+	}
+	five := big.NewInt(5)
+	for {
+		q, m := new(big.Int).QuoRem(rest, five, r)
+		if m.Sign() != 0 {
+			break
+		}
+		rest = q
+		fives++
+	}
+	var res *BigDecimal
+	if rest.Cmp(big.NewInt(1)) == 0 {
+		k := twos
+		if fives > k {
+			k = fives
+		}
+		u := new(big.Int).Mul(num, pow10(k))
+		u.Quo(u, den)
+		res = (&BigDecimal{unscaled: u, scale: scale + k}).round(mc, false)
+	} else {
+		if mc.Precision <= 0 {
+			panic(RT.NewError("Non-terminating decimal expansion; no exact representable decimal result."))
+		}
+		// Compute at least one digit more than needed and round,
+		// keeping track of whether the remainder was nonzero.
+		shift := mc.Precision - (numDigits(num) - numDigits(den)) + 2
+		for {
+			n, d := new(big.Int).Abs(num), den
+			if shift >= 0 {
+				n.Mul(n, pow10(shift))
+			} else {
+				d = new(big.Int).Mul(d, pow10(-shift))
+			}
+			q, m := new(big.Int).QuoRem(n, d, new(big.Int))
+			if numDigits(q) > mc.Precision {
+				if num.Sign() < 0 {
+					q.Neg(q)
+				}
+				res = (&BigDecimal{unscaled: q, scale: scale + shift}).round(mc, m.Sign() != 0)
+				break
+			}
+			shift++
+		}
+	}
+	if res.scale > scale {
+		return res.stripTrailingZeros(scale)
+	}
+	return res.withScale(scale)
+}
+
+// BigDecimal conversions
+
+func (d *BigDecimal) Int() Int {
+	// TODO: 32-bit issue
+	return Int{I: int(d.BigInt().Int64())}
+}
+
+func (d *BigDecimal) BigInt() *big.Int {
+	if d.scale <= 0 {
+		return new(big.Int).Mul(d.unscaled, pow10(-d.scale))
+	}
+	return new(big.Int).Quo(d.unscaled, pow10(d.scale))
+}
+
+func (d *BigDecimal) Double() Double {
+	f, _ := d.Ratio().Float64()
+	return Double{D: f}
+}
+
+func (d *BigDecimal) BigFloat() *big.Float {
+	f := new(big.Float).SetPrec(computePrecision(d.Text()))
+	return f.SetRat(d.Ratio())
+}
+
+func (d *BigDecimal) Ratio() *big.Rat {
+	if d.scale <= 0 {
+		return new(big.Rat).SetInt(d.BigInt())
+	}
+	return new(big.Rat).SetFrac(d.unscaled, pow10(d.scale))
+}
+
+func (d *BigDecimal) Precision() *big.Int {
+	return MakeMathBigIntFromInt(numDigits(d.unscaled))
+}
+
+// Formats the decimal exactly for %s and %v,
+// and as a floating-point number for the other verbs.
+func (n nativeBigDecimal) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'e', 'E', 'f', 'F', 'g', 'G':
+		n.d.BigFloat().Format(f, verb)
+	default:
+		fmt.Fprintf(f, "%"+fmtFlags(f)+string(verb), n.d.Text())
+	}
+}
+
+func fmtFlags(f fmt.State) string {
+	res := ""
+	for _, c := range "+-# 0" {
+		if f.Flag(int(c)) {
+			res += string(c)
+		}
+	}
+	if w, ok := f.Width(); ok {
+		res += strconv.Itoa(w)
+	}
+	if p, ok := f.Precision(); ok {
+		res += "." + strconv.Itoa(p)
+	}
+	return res
+}
+
+// Ops
+
+func alignScales(x, y Number) (*BigDecimal, *BigDecimal) {
+	a, b := toBigDecimal(x), toBigDecimal(y)
+	if a.scale < b.scale {
+		return a.withScale(b.scale), b
+	}
+	return a, b.withScale(a.scale)
+}
+
+func cmpDecimals(x, y Number) int {
+	a, b := alignScales(x, y)
+	return a.unscaled.Cmp(b.unscaled)
+}
+
+func addDecimals(x, y Number) *BigDecimal {
+	a, b := alignScales(x, y)
+	return &BigDecimal{unscaled: new(big.Int).Add(a.unscaled, b.unscaled), scale: a.scale}
+}
+
+func subtractDecimals(x, y Number) *BigDecimal {
+	a, b := alignScales(x, y)
+	return &BigDecimal{unscaled: new(big.Int).Sub(a.unscaled, b.unscaled), scale: a.scale}
+}
+
+func multiplyDecimals(x, y Number) *BigDecimal {
+	a, b := toBigDecimal(x), toBigDecimal(y)
+	return &BigDecimal{unscaled: new(big.Int).Mul(a.unscaled, b.unscaled), scale: a.scale + b.scale}
+}
+
+// quotientDecimals returns the integer part of x / y,
+// with the preferred scale of x.scale - y.scale.
+func quotientDecimals(x, y Number) *BigDecimal {
+	a, b := toBigDecimal(x), toBigDecimal(y)
+	scale := a.scale - b.scale
+	n, d := a.unscaled, b.unscaled
+	if scale >= 0 {
+		d = new(big.Int).Mul(d, pow10(scale))
+	} else {
+		n = new(big.Int).Mul(n, pow10(-scale))
+	}
+	res := &BigDecimal{unscaled: new(big.Int).Quo(n, d)}
+	if scale < 0 {
+		return res.stripTrailingZeros(scale)
+	}
+	return res.withScale(scale)
+}
+
+func (ops BigDecimalOps) Combine(other Ops) Ops {
+	switch other.(type) {
+	case DoubleOps, BigFloatOps:
+		return other
+	default:
+		return ops
+	}
+}
+
+func (ops BigDecimalOps) Add(x, y Number) Number {
+	return addDecimals(x, y).round(CurrentMathContext(), false)
+}
+
+func (ops BigDecimalOps) Subtract(x, y Number) Number {
+	return subtractDecimals(x, y).round(CurrentMathContext(), false)
+}
+
+func (ops BigDecimalOps) Multiply(x, y Number) Number {
+	return multiplyDecimals(x, y).round(CurrentMathContext(), false)
+}
+
+func (ops BigDecimalOps) Divide(x, y Number) Number {
+	panicOnZero(ops, y)
+	a, b := toBigDecimal(x), toBigDecimal(y)
+	return quoDecimal(a.unscaled, b.unscaled, a.scale-b.scale, CurrentMathContext())
+}
+
+func (ops BigDecimalOps) Quotient(x, y Number) Number {
+	panicOnZero(ops, y)
+	return quotientDecimals(x, y)
+}
+
+func (ops BigDecimalOps) Rem(x, y Number) Number {
+	panicOnZero(ops, y)
+	return subtractDecimals(x, multiplyDecimals(quotientDecimals(x, y), y))
+}
+
+func (ops BigDecimalOps) IsZero(x Number) bool {
+	return toBigDecimal(x).unscaled.Sign() == 0
+}
+
+func (ops BigDecimalOps) Lt(x Number, y Number) bool {
+	return cmpDecimals(x, y) < 0
+}
+
+func (ops BigDecimalOps) Lte(x Number, y Number) bool {
+	return cmpDecimals(x, y) <= 0
+}
+
+func (ops BigDecimalOps) Gt(x Number, y Number) bool {
+	return cmpDecimals(x, y) > 0
+}
+
+func (ops BigDecimalOps) Gte(x Number, y Number) bool {
+	return cmpDecimals(x, y) >= 0
+}
+
+func (ops BigDecimalOps) Eq(x Number, y Number) bool {
+	return cmpDecimals(x, y) == 0
+}
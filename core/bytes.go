@@ -0,0 +1,165 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+type (
+	// Bytes is an immutable sequence of bytes.
+	Bytes struct {
+		InfoHolder
+		b []byte
+	}
+	// bytesSeq is a lazy seq over Bytes; yields Ints on demand.
+	bytesSeq struct {
+		b   []byte
+		off int
+	}
+)
+
+// MakeBytes returns Bytes holding b. The caller must not modify b afterwards.
+func MakeBytes(b []byte) *Bytes {
+	return &Bytes{b: b}
+}
+
+// Bytes returns the underlying byte slice, which must not be modified.
+func (b *Bytes) Bytes() []byte {
+	return b.b
+}
+
+func (b *Bytes) ToString(escape bool) string {
+	return "#bytes \"" + hex.EncodeToString(b.b) + "\""
+}
+
+func (b *Bytes) Format(w io.Writer, indent int) int {
+	s := b.ToString(true)
+	fmt.Fprint(w, s)
+	return indent + len(s)
+}
+
+func (b *Bytes) Equals(other interface{}) bool {
+	switch other := other.(type) {
+	case *Bytes:
+		return bytes.Equal(b.b, other.b)
+	default:
+		return false
+	}
+}
+
+func (b *Bytes) GetType() *Type {
+	return TYPE.Bytes
+}
+
+func (b *Bytes) Hash() uint32 {
+	h := getHash()
+	h.Write(b.b)
+	return h.Sum32()
+}
+
+func (b *Bytes) Compare(other Object) int {
+	b2 := EnsureObjectIsBytes(other, "Cannot compare Bytes: %s")
+	return bytes.Compare(b.b, b2.b)
+}
+
+func (b *Bytes) Count() int {
+	return len(b.b)
+}
+
+func (b *Bytes) Seq() Seq {
+	return &bytesSeq{b: b.b}
+}
+
+func (b *Bytes) At(i int) Object {
+	return Int{I: int(b.b[i])}
+}
+
+func (b *Bytes) Nth(i int) Object {
+	if i < 0 || i >= len(b.b) {
+		panic(RT.NewError(fmt.Sprintf("Index %d is out of bounds [0..%d]", i, len(b.b)-1)))
+	}
+	return b.At(i)
+}
+
+func (b *Bytes) TryNth(i int, d Object) Object {
+	if i < 0 || i >= len(b.b) {
+		return d
+	}
+	return b.At(i)
+}
+
+// Subbytes returns the bytes in [start, end). The result shares memory with b,
+// which is safe since Bytes are immutable.
+func (b *Bytes) Subbytes(start, end int) *Bytes {
+	if start < 0 || start > len(b.b) {
+		panic(RT.NewError(fmt.Sprintf("Bytes index out of range: %d", start)))
+	}
+	if end < start || end > len(b.b) {
+		panic(RT.NewError(fmt.Sprintf("Bytes index out of range: %d", end)))
+	}
+	return &Bytes{b: b.b[start:end:end]}
+}
+
+func (seq *bytesSeq) Seq() Seq    { return seq }
+func (seq *bytesSeq) sequential() {}
+
+func (seq *bytesSeq) First() Object {
+	if seq.off >= len(seq.b) {
+		return NIL
+	}
+	return Int{I: int(seq.b[seq.off])}
+}
+
+func (seq *bytesSeq) Rest() Seq {
+	if seq.off+1 >= len(seq.b) {
+		return EmptyList
+	}
+	return &bytesSeq{b: seq.b, off: seq.off + 1}
+}
+
+func (seq *bytesSeq) IsEmpty() bool {
+	return seq.off >= len(seq.b)
+}
+
+func (seq *bytesSeq) Cons(obj Object) Seq {
+	return &ConsSeq{first: obj, rest: seq}
+}
+
+func (seq *bytesSeq) Equals(other interface{}) bool {
+	return IsSeqEqual(seq, other)
+}
+
+func (seq *bytesSeq) ToString(escape bool) string {
+	return SeqToString(seq, escape)
+}
+
+func (seq *bytesSeq) GetInfo() *ObjectInfo               { return nil }
+func (seq *bytesSeq) WithInfo(info *ObjectInfo) Object   { return seq }
+func (seq *bytesSeq) GetType() *Type                     { return TYPE.BytesSeq }
+func (seq *bytesSeq) Hash() uint32                       { return hashOrdered(seq) }
+func (seq *bytesSeq) WithMeta(meta Map) Object           { return seq }
+func (seq *bytesSeq) Pprint(w io.Writer, indent int) int { return pprintSeq(seq, w, indent) }
+func (seq *bytesSeq) Format(w io.Writer, indent int) int { return formatSeq(seq, w, indent) }
+
+// ToBytes returns the content of a String or Bytes object.
+func ToBytes(obj Object, pattern string) []byte {
+	switch obj := obj.(type) {
+	case String:
+		return []byte(obj.S)
+	case *Bytes:
+		return obj.b
+	default:
+		panic(FailObject(obj, "String or Bytes", pattern))
+	}
+}
+
+// MakeBinary returns b as Bytes if like is Bytes, and as a String otherwise.
+// Used by functions that return binary data of the same type they were given.
+func MakeBinary(like Object, b []byte) Object {
+	if _, ok := like.(*Bytes); ok {
+		return MakeBytes(b)
+	}
+	return MakeString(string(b))
+}
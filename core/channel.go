@@ -1,11 +1,15 @@
 package core
 
 import (
+	"math/rand"
+	"reflect"
+	"strconv"
 	"unsafe"
 )
 
 type (
 	ChannelReceiveStatus int
+	ChannelBufferKind    int
 
 	FutureResult struct {
 		value Object
@@ -15,6 +19,17 @@ type (
 		ch       chan FutureResult
 		isClosed bool
 		hash     uint32
+		bufKind  ChannelBufferKind
+	}
+	// ChannelBuffer describes the buffer of a channel created by chan.
+	// Puts to channels with sliding or dropping buffers never block.
+	ChannelBuffer struct {
+		kind ChannelBufferKind
+		size int
+	}
+	altsOp struct {
+		ch  *Channel
+		val Object // nil for takes
 	}
 )
 
@@ -24,6 +39,12 @@ const (
 	ChannelReceiveDone
 )
 
+const (
+	FixedBuffer ChannelBufferKind = iota
+	SlidingBuffer
+	DroppingBuffer
+)
+
 func MakeFutureResult(value Object, err Error) FutureResult {
 	return FutureResult{value: value, err: err}
 }
@@ -58,6 +79,12 @@ func MakeChannel(ch chan FutureResult) *Channel {
 	return res
 }
 
+func MakeBufferedChannel(buf *ChannelBuffer) *Channel {
+	res := MakeChannel(make(chan FutureResult, buf.size))
+	res.bufKind = buf.kind
+	return res
+}
+
 func ExtractChannel(args []Object, index int) *Channel {
 	return EnsureArgIsChannel(args, index)
 }
@@ -79,7 +106,53 @@ func (ch *Channel) Send(value Object) (ok bool) {
 			ok = false
 		}
 	}()
+	if ch.bufKind != FixedBuffer {
+		ch.offer(MakeFutureResult(value, nil))
+		return
+	}
+	ch.ch <- MakeFutureResult(value, nil)
+	return
+}
+
+// offer puts v on a channel with a sliding or dropping buffer
+// without blocking. Must not be called for closed channels.
+func (ch *Channel) offer(v FutureResult) {
+	for {
+		select {
+		case ch.ch <- v:
+			return
+		default:
+		}
+		if ch.bufKind == DroppingBuffer {
+			return
+		}
+		select {
+		case <-ch.ch:
+		default:
+		}
+	}
+}
+
+// Put puts value on the channel, releasing the GIL while blocked.
+// Returns false if the channel is closed.
+func (ch *Channel) Put(value Object) (ok bool) {
+	if ch.isClosed {
+		return false
+	}
+	if ch.bufKind != FixedBuffer {
+		ch.offer(MakeFutureResult(value, nil))
+		return true
+	}
+	ok = true
+	defer func() {
+		if r := recover(); r != nil {
+			RT.GIL.Lock()
+			ok = false
+		}
+	}()
+	RT.GIL.Unlock()
 	ch.ch <- MakeFutureResult(value, nil)
+	RT.GIL.Lock()
 	return
 }
 
@@ -101,3 +174,154 @@ func (ch *Channel) Receive(done <-chan struct{}) (Object, ChannelReceiveStatus,
 		return NIL, ChannelReceiveDone, nil
 	}
 }
+
+func (b *ChannelBuffer) ToString(escape bool) string {
+	var kind string
+	switch b.kind {
+	case SlidingBuffer:
+		kind = ":sliding"
+	case DroppingBuffer:
+		kind = ":dropping"
+	default:
+		kind = ":fixed"
+	}
+	return "#object[ChannelBuffer {:kind " + kind + ", :size " + strconv.Itoa(b.size) + "}]"
+}
+
+func (b *ChannelBuffer) Equals(other interface{}) bool {
+	return b == other
+}
+
+func (b *ChannelBuffer) GetInfo() *ObjectInfo {
+	return nil
+}
+
+func (b *ChannelBuffer) GetType() *Type {
+	return TYPE.ChannelBuffer
+}
+
+func (b *ChannelBuffer) Hash() uint32 {
+	return HashPtr(uintptr(unsafe.Pointer(b)))
+}
+
+func (b *ChannelBuffer) WithInfo(info *ObjectInfo) Object {
+	return b
+}
+
+func altsResult(val Object, port Object) Object {
+	return NewArrayVectorFrom(val, port)
+}
+
+func altsReceived(res FutureResult, ok bool, ch *Channel) Object {
+	if !ok {
+		return altsResult(NIL, ch)
+	}
+	if res.err != nil {
+		panic(res.err)
+	}
+	return altsResult(res.value, ch)
+}
+
+// tryPut attempts to complete a put without blocking.
+func (op altsOp) tryPut() (res Object, ready bool) {
+	if op.ch.isClosed {
+		return altsResult(Boolean{B: false}, op.ch), true
+	}
+	if op.ch.bufKind != FixedBuffer {
+		op.ch.offer(MakeFutureResult(op.val, nil))
+		return altsResult(Boolean{B: true}, op.ch), true
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			res, ready = altsResult(Boolean{B: false}, op.ch), true
+		}
+	}()
+	select {
+	case op.ch.ch <- MakeFutureResult(op.val, nil):
+		return altsResult(Boolean{B: true}, op.ch), true
+	default:
+		return nil, false
+	}
+}
+
+func (op altsOp) tryTake() (Object, bool) {
+	select {
+	case res, ok := <-op.ch.ch:
+		return altsReceived(res, ok, op.ch), true
+	default:
+		return nil, false
+	}
+}
+
+// Alts completes at most one of ops, see alts!.
+// If no operation is ready immediately and dflt is not nil,
+// returns [dflt :default]. Otherwise blocks, releasing the GIL,
+// until one of the operations completes.
+func Alts(ops []altsOp, priority bool, dflt Object) Object {
+	order := make([]int, len(ops))
+	if priority {
+		for i := range order {
+			order[i] = i
+		}
+	} else {
+		order = rand.Perm(len(ops))
+	}
+	for _, i := range order {
+		op := ops[i]
+		var res Object
+		var ready bool
+		if op.val == nil {
+			res, ready = op.tryTake()
+		} else {
+			res, ready = op.tryPut()
+		}
+		if ready {
+			return res
+		}
+	}
+	if dflt != nil {
+		return altsResult(dflt, MakeKeyword("default"))
+	}
+	cases := make([]reflect.SelectCase, len(ops))
+	for i, op := range ops {
+		if op.val == nil {
+			cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(op.ch.ch)}
+		} else {
+			cases[i] = reflect.SelectCase{
+				Dir:  reflect.SelectSend,
+				Chan: reflect.ValueOf(op.ch.ch),
+				Send: reflect.ValueOf(MakeFutureResult(op.val, nil)),
+			}
+		}
+	}
+	chosen, recv, recvOK, sendFailed := altsSelect(cases)
+	if sendFailed {
+		// A channel was closed while we were trying to put on it.
+		for _, op := range ops {
+			if op.val != nil && op.ch.isClosed {
+				return altsResult(Boolean{B: false}, op.ch)
+			}
+		}
+		panic(RT.NewError("alts! put failed"))
+	}
+	op := ops[chosen]
+	if op.val != nil {
+		return altsResult(Boolean{B: true}, op.ch)
+	}
+	if !recvOK {
+		return altsResult(NIL, op.ch)
+	}
+	return altsReceived(recv.Interface().(FutureResult), true, op.ch)
+}
+
+func altsSelect(cases []reflect.SelectCase) (chosen int, recv reflect.Value, recvOK bool, sendFailed bool) {
+	RT.GIL.Unlock()
+	defer RT.GIL.Lock()
+	defer func() {
+		if r := recover(); r != nil {
+			sendFailed = true
+		}
+	}()
+	chosen, recv, recvOK = reflect.Select(cases)
+	return
+}
@@ -328,10 +328,6 @@ func (v *Var) Expr() Expr {
 	return v.expr
 }
 
-func (v Var) Namespace() *Namespace {
-	return v.ns
-}
-
 func (v *VarRefExpr) Var() *Var {
 	return v.vr
 }
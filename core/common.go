@@ -10,13 +10,37 @@ import (
 
 var exitCallbacks []func()
 
+// SessionExit is what ExitJoker panics with while a socket repl server
+// runs, so that (exit) ends a session rather than the whole server.
+type SessionExit struct {
+	Code int
+}
+
+// EXIT_SESSION is set while a socket repl server runs. It ends the
+// session whose code runs. Goroutines started by future, go and send
+// keep the value it had when they were started (see endSession).
+var EXIT_SESSION func()
+
 func ExitJoker(rc int) {
+	if EXIT_SESSION != nil {
+		panic(SessionExit{Code: rc})
+	}
 	for _, f := range exitCallbacks {
 		f()
 	}
 	os.Exit(rc)
 }
 
+// endSession ends the session that started a goroutine, when code
+// in the goroutine calls exit, and returns the error that becomes the
+// goroutine's result.
+func endSession(exitSession func()) Error {
+	if exitSession != nil {
+		exitSession()
+	}
+	return RT.NewError("exit called outside the session's goroutine, ending the session")
+}
+
 func OnExit(f func()) {
 	exitCallbacks = append(exitCallbacks, f)
 }
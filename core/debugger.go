@@ -0,0 +1,326 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Debugger pauses evaluation at breakpoints, at calls to (break) and
+	// when stepping, and then reads commands and expressions to evaluate
+	// in the paused frame from Stdin.
+	//
+	// Evaluation pauses before a call expression is evaluated. Breakpoints
+	// are set on vars ("ns/name"), pausing at every call of the var, or on
+	// positions ("file:line"), pausing at the outermost call on that line.
+	Debugger struct {
+		in          *bufio.Reader
+		breakpoints map[string]bool
+		calls       []debugCall
+		step        stepMode
+		stepDepth   int
+		paused      bool
+		eof         bool
+	}
+	debugCall struct {
+		expr *CallExpr
+		env  *LocalEnv
+	}
+	// debugInfo is what the debugger needs to know about call expressions
+	// parsed while it's enabled. Evaluation only pauses at such calls.
+	debugInfo struct {
+		locals *Bindings
+	}
+	stepMode int
+)
+
+const (
+	stepNone stepMode = iota
+	stepInto
+	stepOver
+	stepOut
+)
+
+// DEBUGGER is the debugger in effect, or nil if debugging is disabled.
+// Evaluation only pauses in code parsed while the debugger is enabled,
+// so that e.g. stepping doesn't go into joker.core functions.
+var DEBUGGER *Debugger
+
+const debuggerHelp = `Debugger commands:
+  :c, :continue         Continue to the next breakpoint
+  :s, :step             Step into the next call
+  :n, :next             Step over the current call
+  :o, :out              Step out of the current function
+  :l, :locals           Print local bindings
+  :bt, :where           Print the call stack
+  :b, :break <target>   Set a breakpoint on a var (ns/name) or position (file:line)
+  :d, :delete <target>  Delete a breakpoint
+  :breakpoints          List breakpoints
+  :abort                Abort evaluation
+  :h, :help             Print this help
+Anything else is evaluated in the paused frame.`
+
+func NewDebugger() *Debugger {
+	return &Debugger{breakpoints: make(map[string]bool)}
+}
+
+// EnableDebugger makes evaluation pause as directed by d.
+func EnableDebugger(d *Debugger) {
+	DEBUGGER = d
+}
+
+// AddBreakpoint sets a breakpoint on a var ("ns/name") or a position
+// ("file:line").
+func (d *Debugger) AddBreakpoint(target string) error {
+	if i := strings.LastIndex(target, ":"); i > 0 {
+		if _, err := strconv.Atoi(target[i+1:]); err != nil {
+			return fmt.Errorf("invalid line number in breakpoint %s", target)
+		}
+	} else if !strings.Contains(target, "/") || strings.HasSuffix(target, "/") {
+		return fmt.Errorf("breakpoint must be ns/name or file:line, got %s", target)
+	}
+	d.breakpoints[target] = true
+	return nil
+}
+
+func (d *Debugger) evalCall(expr *CallExpr, env *LocalEnv) Object {
+	n := len(d.calls)
+	d.calls = append(d.calls, debugCall{expr: expr, env: env})
+	defer func() {
+		d.calls = d.calls[:n]
+	}()
+	if !d.paused && expr.debug != nil {
+		if reason := d.breakReason(); reason != "" {
+			d.pause(n+1, reason)
+		}
+	}
+	return expr.eval(env)
+}
+
+// breakReason returns why evaluation should pause before the innermost
+// call, or "" if it shouldn't.
+func (d *Debugger) breakReason() string {
+	depth := len(d.calls)
+	switch {
+	case d.step == stepInto,
+		d.step == stepOver && depth <= d.stepDepth,
+		d.step == stepOut && depth < d.stepDepth:
+		return "Step"
+	}
+	if len(d.breakpoints) == 0 {
+		return ""
+	}
+	expr := d.calls[depth-1].expr
+	if ref, ok := expr.callable.(*VarRefExpr); ok && d.breakpoints[ref.vr.Name()] {
+		return "Breakpoint " + ref.vr.Name()
+	}
+	if depth > 1 {
+		// Only pause at the outermost call on the line.
+		parent := d.calls[depth-2].expr
+		if parent.startLine == expr.startLine && parent.Filename() == expr.Filename() {
+			return ""
+		}
+	}
+	line := ":" + strconv.Itoa(expr.startLine)
+	filename := expr.Filename()
+	for target := range d.breakpoints {
+		if !strings.HasSuffix(target, line) {
+			continue
+		}
+		file := strings.TrimSuffix(target, line)
+		if filename == file || strings.HasSuffix(filename, "/"+file) {
+			return "Breakpoint " + target
+		}
+	}
+	return ""
+}
+
+// breakHere pauses at the call to break (one level up from the call
+// to break__ in its definition).
+func (d *Debugger) breakHere() {
+	depth := len(d.calls) - 1
+	if d.paused || depth < 1 || d.calls[depth-1].expr.debug == nil {
+		return
+	}
+	d.pause(depth, "Break")
+}
+
+// pause reads and executes debugger commands until one of them
+// continues evaluation of the call at depth in the debugger's calls.
+func (d *Debugger) pause(depth int, reason string) {
+	call := d.calls[depth-1]
+	d.paused = true
+	d.step = stepNone
+	defer func() {
+		d.paused = false
+	}()
+	fmt.Fprintf(Stdout, "%s at %s:%d:%d (%s)\n", reason, call.expr.Filename(), call.expr.startLine, call.expr.startColumn, call.expr.Name())
+	for {
+		line, ok := d.readLine()
+		if !ok {
+			return
+		}
+		if !strings.HasPrefix(line, ":") {
+			d.evalInFrame(line, call)
+			continue
+		}
+		fields := strings.Fields(line)
+		arg := ""
+		if len(fields) > 1 {
+			arg = fields[1]
+		}
+		switch fields[0] {
+		case ":c", ":continue":
+			return
+		case ":s", ":step":
+			d.step = stepInto
+			return
+		case ":n", ":next":
+			d.step, d.stepDepth = stepOver, depth
+			return
+		case ":o", ":out":
+			d.step, d.stepDepth = stepOut, depth
+			return
+		case ":l", ":locals":
+			for _, b := range visibleBindings(call.expr.debug.locals, call.env) {
+				fmt.Fprintf(Stdout, "%s = %s\n", b.name.ToString(false), b.value.ToString(true))
+			}
+		case ":bt", ":where":
+			fmt.Fprintln(Stdout, RT.callstack.String())
+		case ":b", ":break":
+			if err := d.AddBreakpoint(arg); err != nil {
+				fmt.Fprintln(Stdout, "Error:", err)
+			}
+		case ":d", ":delete":
+			if !d.breakpoints[arg] {
+				fmt.Fprintln(Stdout, "No breakpoint", arg)
+			}
+			delete(d.breakpoints, arg)
+		case ":breakpoints":
+			targets := make([]string, 0, len(d.breakpoints))
+			for target := range d.breakpoints {
+				targets = append(targets, target)
+			}
+			sort.Strings(targets)
+			for _, target := range targets {
+				fmt.Fprintln(Stdout, target)
+			}
+		case ":abort":
+			panic(RT.NewErrorWithPos("Evaluation aborted by debugger", call.expr.Position))
+		case ":h", ":help":
+			fmt.Fprintln(Stdout, debuggerHelp)
+		default:
+			fmt.Fprintln(Stdout, "Unknown debugger command "+fields[0]+", use :help to list commands")
+		}
+	}
+}
+
+// readLine reads the next non-empty line of input. Once input is
+// exhausted, evaluation continues without pausing for input.
+func (d *Debugger) readLine() (string, bool) {
+	if d.in == nil {
+		d.in = bufio.NewReader(Stdin)
+	}
+	for !d.eof {
+		fmt.Fprint(Stdout, "debug=> ")
+		line, err := d.in.ReadString('\n')
+		if err == io.EOF {
+			d.eof = true
+			fmt.Fprintln(Stdout)
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			return line, true
+		}
+	}
+	return "", false
+}
+
+// evalInFrame evaluates the forms in line with the locals of call
+// in scope and prints their values.
+func (d *Debugger) evalInFrame(line string, call debugCall) {
+	defer func() {
+		if r := recover(); r != nil {
+			switch r := r.(type) {
+			case *ParseError, Error:
+				fmt.Fprintln(Stdout, r)
+			default:
+				panic(r)
+			}
+		}
+	}()
+	ctx := &ParseContext{GlobalEnv: GLOBAL_ENV, localBindings: debugBindings(call.expr.debug.locals, call.env)}
+	reader := NewReader(strings.NewReader(line), "<debug>")
+	for {
+		obj, err := TryRead(reader)
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			fmt.Fprintln(Stdout, err)
+			return
+		}
+		fmt.Fprintln(Stdout, Eval(Parse(obj, ctx), call.env).ToString(true))
+	}
+}
+
+type localBinding struct {
+	name  Symbol
+	index int
+	value Object
+}
+
+// visibleBindings returns the local bindings of locals that have
+// values in env, outermost first, leaving out shadowed ones.
+func visibleBindings(locals *Bindings, env *LocalEnv) []localBinding {
+	var frames [][]localBinding
+	seen := make(map[*string]bool)
+	for b := locals; b != nil; b = b.parent {
+		for env != nil && env.frame > b.frame {
+			env = env.parent
+		}
+		if env == nil || env.frame != b.frame {
+			continue
+		}
+		var frame []localBinding
+		for name, binding := range b.bindings {
+			if seen[name] || binding.index >= len(env.bindings) {
+				continue
+			}
+			seen[name] = true
+			frame = append(frame, localBinding{name: binding.name, index: binding.index, value: env.bindings[binding.index]})
+		}
+		sort.Slice(frame, func(i, j int) bool { return frame[i].index < frame[j].index })
+		frames = append(frames, frame)
+	}
+	var res []localBinding
+	for i := len(frames) - 1; i >= 0; i-- {
+		res = append(res, frames[i]...)
+	}
+	return res
+}
+
+// debugBindings returns a copy of locals with just the bindings that
+// have values in env, for parsing code to evaluate in env.
+func debugBindings(locals *Bindings, env *LocalEnv) *Bindings {
+	if locals == nil {
+		return nil
+	}
+	res := &Bindings{
+		bindings: make(map[*string]*Binding),
+		parent:   debugBindings(locals.parent, env),
+		frame:    locals.frame,
+	}
+	for env != nil && env.frame > locals.frame {
+		env = env.parent
+	}
+	for name, binding := range locals.bindings {
+		if env != nil && env.frame == locals.frame && binding.index < len(env.bindings) {
+			res.bindings[name] = binding
+		}
+	}
+	return res
+}
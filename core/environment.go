@@ -2,12 +2,29 @@ package core
 
 import (
 	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"runtime/debug"
 	"strconv"
 	"strings"
 )
 
+// DEFAULT_MAX_CALL_DEPTH is the default value of *max-call-depth*: the
+// depth at which calls would use up the Go runtime's maximum stack size
+// (see debug.SetMaxStack), allowing callStackBytes per call.
+var DEFAULT_MAX_CALL_DEPTH = maxStackSize() / callStackBytes
+
+// callStackBytes is more than the Go stack a Joker call takes, including
+// evaluating its arguments (e.g. from inside a map literal).
+const callStackBytes = 8 << 10
+
+func maxStackSize() int {
+	n := debug.SetMaxStack(math.MaxInt)
+	debug.SetMaxStack(n)
+	return n
+}
+
 var (
 	Stdin          io.Reader = os.Stdin
 	Stdout         io.Writer = os.Stdout
@@ -17,22 +34,29 @@ var (
 
 type (
 	Env struct {
-		Namespaces    map[*string]*Namespace
-		CoreNamespace *Namespace
-		stdout        *Var
-		stdin         *Var
-		stderr        *Var
-		printReadably *Var
-		file          *Var
-		MainFile      *Var
-		args          *Var
-		classPath     *Var
-		ns            *Var
-		NS_VAR        *Var
-		IN_NS_VAR     *Var
-		version       *Var
-		libs          *Var
-		Features      Set
+		Namespaces         map[*string]*Namespace
+		CoreNamespace      *Namespace
+		stdout             *Var
+		stdin              *Var
+		stderr             *Var
+		printReadably      *Var
+		printLength        *Var
+		printLevel         *Var
+		printMeta          *Var
+		printDup           *Var
+		printNamespaceMaps *Var
+		mathContext        *Var
+		maxCallDepth       *Var
+		file               *Var
+		MainFile           *Var
+		args               *Var
+		classPath          *Var
+		ns                 *Var
+		NS_VAR             *Var
+		IN_NS_VAR          *Var
+		version            *Var
+		libs               *Var
+		Features           Set
 	}
 )
 
@@ -89,6 +113,11 @@ func (env *Env) InitEnv(stdin io.Reader, stdout, stderr io.Writer, args []string
 	env.stdout.Value = MakeIOWriter(stdout)
 	env.stderr.Value = MakeIOWriter(stderr)
 	env.SetEnvArgs(args)
+	env.SetMaxCallDepth(DEFAULT_MAX_CALL_DEPTH)
+}
+
+func (env *Env) SetMaxCallDepth(n int) {
+	env.maxCallDepth.Value = MakeInt(n)
 }
 
 func (env *Env) SetStdIO(stdin, stdout, stderr Object) {
@@ -38,6 +38,36 @@ func NewEnv() *Env {
 	res.classPath.isPrivate = true
 	res.printReadably = res.CoreNamespace.Intern(MakeSymbol("*print-readably*"))
 	res.printReadably.Value = Boolean{B: true}
+	res.printLength = res.CoreNamespace.InternVar("*print-length*", NIL,
+		MakeMeta(nil, `When set to a number n, pr and friends print at most n items of
+			each collection, followed by "...". nil (the default) means no limit.`, "1.10"))
+	res.printLength.isDynamic = true
+	res.printLevel = res.CoreNamespace.InternVar("*print-level*", NIL,
+		MakeMeta(nil, `When set to a number n, pr and friends print collections nested
+			more than n levels deep as "#". nil (the default) means no limit.`, "1.10"))
+	res.printLevel.isDynamic = true
+	res.printMeta = res.CoreNamespace.InternVar("*print-meta*", Boolean{B: false},
+		MakeMeta(nil, `When true, pr and friends print the metadata of collections and
+			symbols, in a form that can be read back. Defaults to false.`, "1.10"))
+	res.printMeta.isDynamic = true
+	res.printDup = res.CoreNamespace.InternVar("*print-dup*", Boolean{B: false},
+		MakeMeta(nil, `When true, pr and friends consult print-dup before print-method.
+			Defaults to false.`, "1.10"))
+	res.printDup.isDynamic = true
+	res.printNamespaceMaps = res.CoreNamespace.InternVar("*print-namespace-maps*", Boolean{B: false},
+		MakeMeta(nil, `When true, pr and friends print maps whose keys all share a namespace
+			using the #:ns{...} syntax. Defaults to false.`, "1.10"))
+	res.printNamespaceMaps.isDynamic = true
+	res.mathContext = res.CoreNamespace.InternVar("*math-context*", NIL,
+		MakeMeta(nil, `The precision and rounding mode used by BigDecimal operations,
+			as a map with :precision and :rounding keys, or nil for exact arithmetic.
+			Usually bound with with-precision.`, "1.10"))
+	res.mathContext.isDynamic = true
+	res.maxCallDepth = res.CoreNamespace.InternVar("*max-call-depth*", MakeInt(DEFAULT_MAX_CALL_DEPTH),
+		MakeMeta(nil, `The maximum depth of the call stack (and of nested macro expansions).
+			Exceeding it throws StackOverflowError rather than crashing Joker.
+			0 or nil means no limit. Set with the --max-call-depth option.`, "1.10"))
+	res.maxCallDepth.isDynamic = true
 	res.CoreNamespace.InternVar("*repl*", Boolean{B: false},
 		MakeMeta(nil, "true if Joker is running in repl mode", "1.5"))
 	res.CoreNamespace.InternVar("*linter-mode*", Boolean{B: LINTER_MODE},
@@ -18,6 +18,12 @@ type (
 		rt   *Runtime
 		hash uint32
 	}
+	// StackOverflowError is thrown when a call would make the call stack
+	// deeper than *max-call-depth* (or macro expansion nest deeper than
+	// that), before the Go runtime runs out of stack.
+	StackOverflowError struct {
+		EvalError
+	}
 	Frame struct {
 		traceable Traceable
 	}
@@ -28,6 +34,7 @@ type (
 		callstack   *Callstack
 		currentExpr Expr
 		GIL         sync.Mutex
+		limits      limits
 	}
 )
 
@@ -66,6 +73,13 @@ func (rt *Runtime) NewErrorWithPos(msg string, pos Position) *EvalError {
 	}
 }
 
+// Stacktraces deeper than stacktraceHead+stacktraceTail frames
+// only show that many outermost and innermost frames.
+const (
+	stacktraceHead = 20
+	stacktraceTail = 80
+)
+
 func (rt *Runtime) stacktrace() string {
 	b := getBuffer()
 	defer putBuffer(b)
@@ -74,7 +88,15 @@ func (rt *Runtime) stacktrace() string {
 		pos = rt.currentExpr.Pos()
 	}
 	name := "global"
-	for _, f := range rt.callstack.frames {
+	frames := rt.callstack.frames
+	for i, f := range frames {
+		if i == stacktraceHead && len(frames) > stacktraceHead+stacktraceTail {
+			b.WriteString(fmt.Sprintf("  ... %d more frames\n", len(frames)-stacktraceHead-stacktraceTail))
+		}
+		if i >= stacktraceHead && i < len(frames)-stacktraceTail {
+			name = f.traceable.Name()
+			continue
+		}
 		framePos := f.traceable.Pos()
 		b.WriteString(fmt.Sprintf("  %s %s:%d:%d\n", name, framePos.Filename(), framePos.startLine, framePos.startColumn))
 		name = f.traceable.Name()
@@ -90,15 +112,36 @@ func (rt *Runtime) pushFrame() {
 	// TODO: this is all wrong. We cannot rely on
 	// currentExpr for stacktraces. Instead, each Callable
 	// should know it's name / position.
-	var tr Traceable
-	if rt.currentExpr != nil {
-		tr = rt.currentExpr.(Traceable)
-	} else {
+	tr, ok := rt.currentExpr.(Traceable)
+	if !ok {
+		// E.g. watches called when a def form changes the root binding of a var.
 		tr = &CallExpr{}
 	}
+	if max := MaxCallDepth(); max > 0 && len(rt.callstack.frames) >= max {
+		panic(rt.NewStackOverflowError(max))
+	}
 	rt.callstack.pushFrame(Frame{traceable: tr})
 }
 
+// MaxCallDepth returns the value of *max-call-depth*, or 0 if the depth
+// of the call stack is not limited.
+func MaxCallDepth() int {
+	if n, ok := GLOBAL_ENV.maxCallDepth.Value.(Int); ok {
+		return n.I
+	}
+	return 0
+}
+
+func (rt *Runtime) NewStackOverflowError(max int) *StackOverflowError {
+	return &StackOverflowError{*rt.NewError(fmt.Sprintf("Stack overflow: call depth exceeds *max-call-depth* (%d)", max))}
+}
+
+// newMacroStackOverflowError is thrown when macro expansions of the form
+// at pos are nested deeper than max.
+func (rt *Runtime) newMacroStackOverflowError(max int, pos Position) *StackOverflowError {
+	return &StackOverflowError{*rt.NewErrorWithPos(fmt.Sprintf("Stack overflow: macro expansions nested deeper than *max-call-depth* (%d)", max), pos)}
+}
+
 func (rt *Runtime) popFrame() {
 	rt.callstack.popFrame()
 }
@@ -183,6 +226,18 @@ func (err *EvalError) Error() string {
 	}
 }
 
+func (err *StackOverflowError) Equals(other interface{}) bool {
+	return err == other
+}
+
+func (err *StackOverflowError) GetType() *Type {
+	return TYPE.StackOverflowError
+}
+
+func (err *StackOverflowError) Hash() uint32 {
+	return HashPtr(uintptr(unsafe.Pointer(err)))
+}
+
 func (expr *VarRefExpr) Eval(env *LocalEnv) Object {
 	return expr.vr.Resolve()
 }
@@ -255,7 +310,7 @@ func (expr *SetExpr) Eval(env *LocalEnv) Object {
 
 func (expr *DefExpr) Eval(env *LocalEnv) Object {
 	if expr.value != nil {
-		expr.vr.Value = Eval(expr.value, env)
+		expr.vr.setValue(expr.vr, &expr.vr.Value, Eval(expr.value, env))
 	}
 	meta := EmptyArrayMap()
 	meta.Add(KEYWORDS.line, Int{I: expr.startLine})
@@ -289,6 +344,14 @@ func evalSeq(exprs []Expr, env *LocalEnv) []Object {
 }
 
 func (expr *CallExpr) Eval(env *LocalEnv) Object {
+	RT.step()
+	if DEBUGGER != nil {
+		return DEBUGGER.evalCall(expr, env)
+	}
+	return expr.eval(env)
+}
+
+func (expr *CallExpr) eval(env *LocalEnv) Object {
 	callable := Eval(expr.callable, env)
 	switch callable := callable.(type) {
 	case Callable:
@@ -369,6 +432,7 @@ func evalBody(body []Expr, env *LocalEnv) Object {
 func evalLoop(body []Expr, env *LocalEnv) Object {
 	var res Object = NIL
 loop:
+	RT.step()
 	for _, expr := range body {
 		res = Eval(expr, env)
 	}
@@ -439,6 +503,10 @@ func TryEval(expr Expr) (obj Object, err error) {
 			switch r.(type) {
 			case *EvalError:
 				err = r.(error)
+			case *StackOverflowError:
+				err = r.(error)
+			case *InterruptedError:
+				err = r.(error)
 			case *ExInfo:
 				err = r.(error)
 			default:
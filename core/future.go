@@ -0,0 +1,231 @@
+package core
+
+import (
+	"time"
+	"unsafe"
+)
+
+type (
+	// BlockingDeref is implemented by references whose value may not be
+	// available yet, such as futures and promises.
+	BlockingDeref interface {
+		Deref
+		// DerefTimeout waits at most timeout for the value.
+		// The second return value is false if the wait timed out.
+		DerefTimeout(timeout time.Duration) (Object, bool)
+	}
+	Future struct {
+		done      chan struct{}
+		isDone    bool
+		cancelled bool
+		result    FutureResult
+	}
+	Promise struct {
+		done      chan struct{}
+		delivered bool
+		value     Object
+	}
+)
+
+// waitReleasingGIL waits until done is closed, releasing the GIL while blocked.
+// A negative timeout means wait forever. Returns false on timeout.
+func waitReleasingGIL(done chan struct{}, timeout time.Duration) bool {
+	select {
+	case <-done:
+		return true
+	default:
+	}
+	RT.GIL.Unlock()
+	defer RT.GIL.Lock()
+	if timeout < 0 {
+		<-done
+		return true
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+func MakeFuture(f Callable) *Future {
+	res := &Future{done: make(chan struct{})}
+	exitSession := EXIT_SESSION
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				switch r := r.(type) {
+				case Error:
+					res.complete(MakeFutureResult(NIL, r))
+				case SessionExit:
+					res.complete(MakeFutureResult(NIL, endSession(exitSession)))
+				default:
+					RT.GIL.Unlock()
+					panic(r)
+				}
+			}
+			RT.GIL.Unlock()
+		}()
+
+		RT.GIL.Lock()
+		EXIT_SESSION = exitSession
+		res.complete(MakeFutureResult(f.Call([]Object{}), nil))
+	}()
+	return res
+}
+
+// complete must be called with the GIL held.
+func (f *Future) complete(result FutureResult) {
+	if f.isDone {
+		return
+	}
+	f.result = result
+	f.isDone = true
+	close(f.done)
+}
+
+// Cancel marks the future as cancelled unless it has already completed.
+// Go has no way to stop a goroutine, so the body keeps running but its
+// result is discarded. Must be called with the GIL held.
+func (f *Future) Cancel() bool {
+	if f.isDone {
+		return false
+	}
+	f.cancelled = true
+	f.complete(MakeFutureResult(NIL, nil))
+	return true
+}
+
+func (f *Future) IsCancelled() bool {
+	return f.cancelled
+}
+
+func (f *Future) ToString(escape bool) string {
+	switch {
+	case f.cancelled:
+		return "#object[Future {:status :cancelled}]"
+	case !f.isDone:
+		return "#object[Future {:status :pending}]"
+	case f.result.err != nil:
+		return "#object[Future {:status :failed}]"
+	default:
+		return "#object[Future {:status :ready, :val " + f.result.value.ToString(escape) + "}]"
+	}
+}
+
+func (f *Future) Equals(other interface{}) bool {
+	return f == other
+}
+
+func (f *Future) GetInfo() *ObjectInfo {
+	return nil
+}
+
+func (f *Future) GetType() *Type {
+	return TYPE.Future
+}
+
+func (f *Future) Hash() uint32 {
+	return HashPtr(uintptr(unsafe.Pointer(f)))
+}
+
+func (f *Future) WithInfo(info *ObjectInfo) Object {
+	return f
+}
+
+func (f *Future) IsRealized() bool {
+	return f.isDone
+}
+
+func (f *Future) value() Object {
+	if f.cancelled {
+		panic(RT.NewError("Future was cancelled"))
+	}
+	if f.result.err != nil {
+		panic(f.result.err)
+	}
+	return f.result.value
+}
+
+func (f *Future) Deref() Object {
+	waitReleasingGIL(f.done, -1)
+	return f.value()
+}
+
+func (f *Future) DerefTimeout(timeout time.Duration) (Object, bool) {
+	if !waitReleasingGIL(f.done, timeout) {
+		return NIL, false
+	}
+	return f.value(), true
+}
+
+func MakePromise() *Promise {
+	return &Promise{done: make(chan struct{}), value: NIL}
+}
+
+// Deliver sets the value of the promise and wakes up all waiting readers.
+// Returns false if the promise has already been delivered.
+func (p *Promise) Deliver(value Object) bool {
+	if p.delivered {
+		return false
+	}
+	p.value = value
+	p.delivered = true
+	close(p.done)
+	return true
+}
+
+func (p *Promise) ToString(escape bool) string {
+	if !p.delivered {
+		return "#object[Promise {:status :pending}]"
+	}
+	return "#object[Promise {:status :ready, :val " + p.value.ToString(escape) + "}]"
+}
+
+func (p *Promise) Equals(other interface{}) bool {
+	return p == other
+}
+
+func (p *Promise) GetInfo() *ObjectInfo {
+	return nil
+}
+
+func (p *Promise) GetType() *Type {
+	return TYPE.Promise
+}
+
+func (p *Promise) Hash() uint32 {
+	return HashPtr(uintptr(unsafe.Pointer(p)))
+}
+
+func (p *Promise) WithInfo(info *ObjectInfo) Object {
+	return p
+}
+
+func (p *Promise) IsRealized() bool {
+	return p.delivered
+}
+
+func (p *Promise) Deref() Object {
+	waitReleasingGIL(p.done, -1)
+	return p.value
+}
+
+func (p *Promise) DerefTimeout(timeout time.Duration) (Object, bool) {
+	if !waitReleasingGIL(p.done, timeout) {
+		return NIL, false
+	}
+	return p.value, true
+}
+
+// Call delivers the value to the promise, same as deliver.
+func (p *Promise) Call(args []Object) Object {
+	CheckArity(args, 1, 1)
+	if p.Deliver(args[0]) {
+		return p
+	}
+	return NIL
+}
@@ -17,6 +17,7 @@ import (
 	"strings"
 
 	_ "github.com/candid82/joker/std/html"
+	_ "github.com/candid82/joker/std/json"
 	_ "github.com/candid82/joker/std/string"
 
 	. "github.com/candid82/joker/core"
@@ -64,6 +65,14 @@ var CoreSourceFiles []FileInfo = []FileInfo{
 		Name:     "<joker.tools.cli>",
 		Filename: "tools_cli.joke",
 	},
+	{
+		Name:     "<joker.async>",
+		Filename: "async.joke",
+	},
+	{
+		Name:     "<joker.trace>",
+		Filename: "trace.joke",
+	},
 	{
 		Name:     "<joker.core>",
 		Filename: "linter_all.joke",
@@ -11,6 +11,8 @@ type (
 	Node interface {
 		assoc(shift uint, hash uint32, key Object, val Object, addedLeaf *Box) Node
 		without(shift uint, hash uint32, key Object) Node
+		editAssoc(edit *Edit, shift uint, hash uint32, key Object, val Object, addedLeaf *Box) Node
+		editWithout(edit *Edit, shift uint, hash uint32, key Object, removedLeaf *Box) Node
 		find(shift uint, hash uint32, key Object) *Pair
 		nodeSeq() Seq
 		iter() MapIterator
@@ -22,15 +24,18 @@ type (
 		root  Node
 	}
 	BitmapIndexedNode struct {
+		edit   *Edit
 		bitmap int
 		array  []interface{}
 	}
 	HashCollisionNode struct {
+		edit  *Edit
 		hash  uint32
 		count int
 		array []interface{}
 	}
 	ArrayNode struct {
+		edit  *Edit
 		count int
 		array []Node
 	}
@@ -361,7 +366,7 @@ func (n *ArrayNode) without(shift uint, hash uint32, key Object) Node {
 	}
 	if nn == nil {
 		if n.count <= 8 {
-			return n.pack(uint(idx))
+			return n.pack(nil, uint(idx))
 		}
 		return &ArrayNode{
 			count: n.count - 1,
@@ -375,6 +380,61 @@ func (n *ArrayNode) without(shift uint, hash uint32, key Object) Node {
 	}
 }
 
+func (n *ArrayNode) ensureEditable(edit *Edit) *ArrayNode {
+	if n.edit == edit {
+		return n
+	}
+	array := make([]Node, len(n.array))
+	copy(array, n.array)
+	return &ArrayNode{
+		edit:  edit,
+		count: n.count,
+		array: array,
+	}
+}
+
+func (n *ArrayNode) editAndSet(edit *Edit, i uint32, node Node) *ArrayNode {
+	editable := n.ensureEditable(edit)
+	editable.array[i] = node
+	return editable
+}
+
+func (n *ArrayNode) editAssoc(edit *Edit, shift uint, hash uint32, key Object, val Object, addedLeaf *Box) Node {
+	idx := mask(hash, shift)
+	node := n.array[idx]
+	if node == nil {
+		editable := n.editAndSet(edit, idx, emptyIndexedNode.editAssoc(edit, shift+5, hash, key, val, addedLeaf))
+		editable.count++
+		return editable
+	}
+	nn := node.editAssoc(edit, shift+5, hash, key, val, addedLeaf)
+	if nn == node {
+		return n
+	}
+	return n.editAndSet(edit, idx, nn)
+}
+
+func (n *ArrayNode) editWithout(edit *Edit, shift uint, hash uint32, key Object, removedLeaf *Box) Node {
+	idx := mask(hash, shift)
+	node := n.array[idx]
+	if node == nil {
+		return n
+	}
+	nn := node.editWithout(edit, shift+5, hash, key, removedLeaf)
+	if nn == node {
+		return n
+	}
+	if nn == nil {
+		if n.count <= 8 {
+			return n.pack(edit, uint(idx))
+		}
+		editable := n.editAndSet(edit, idx, nn)
+		editable.count--
+		return editable
+	}
+	return n.editAndSet(edit, idx, nn)
+}
+
 func (n *ArrayNode) find(shift uint, hash uint32, key Object) *Pair {
 	idx := mask(hash, shift)
 	node := n.array[idx]
@@ -388,7 +448,7 @@ func (n *ArrayNode) nodeSeq() Seq {
 	return newArrayNodeSeq(n.array, 0, nil)
 }
 
-func (n *ArrayNode) pack(idx uint) Node {
+func (n *ArrayNode) pack(edit *Edit, idx uint) Node {
 	newArray := make([]interface{}, 2*(n.count-1))
 	j := 1
 	bitmap := 0
@@ -408,6 +468,7 @@ func (n *ArrayNode) pack(idx uint) Node {
 		}
 	}
 	return &BitmapIndexedNode{
+		edit:   edit,
 		bitmap: bitmap,
 		array:  newArray,
 	}
@@ -475,6 +536,70 @@ func (n *HashCollisionNode) without(shift uint, hash uint32, key Object) Node {
 	}
 }
 
+func (n *HashCollisionNode) ensureEditable(edit *Edit) *HashCollisionNode {
+	if n.edit == edit {
+		return n
+	}
+	// Leave room for one more pair.
+	array := make([]interface{}, 2*(n.count+1))
+	copy(array, n.array[:2*n.count])
+	return &HashCollisionNode{
+		edit:  edit,
+		hash:  n.hash,
+		count: n.count,
+		array: array,
+	}
+}
+
+func (n *HashCollisionNode) editAssoc(edit *Edit, shift uint, hash uint32, key Object, val Object, addedLeaf *Box) Node {
+	if hash == n.hash {
+		idx := n.findIndex(key)
+		if idx != -1 {
+			if n.array[idx+1] == val {
+				return n
+			}
+			editable := n.ensureEditable(edit)
+			editable.array[idx+1] = val
+			return editable
+		}
+		addedLeaf.val = addedLeaf
+		editable := n.ensureEditable(edit)
+		if len(editable.array) <= 2*editable.count {
+			array := make([]interface{}, 2*(editable.count+1))
+			copy(array, editable.array)
+			editable.array = array
+		}
+		editable.array[2*editable.count] = key
+		editable.array[2*editable.count+1] = val
+		editable.count++
+		return editable
+	}
+	return (&BitmapIndexedNode{
+		edit:   edit,
+		bitmap: bitpos(n.hash, shift),
+		array:  []interface{}{nil, n, nil, nil},
+	}).editAssoc(edit, shift, hash, key, val, addedLeaf)
+}
+
+func (n *HashCollisionNode) editWithout(edit *Edit, shift uint, hash uint32, key Object, removedLeaf *Box) Node {
+	idx := n.findIndex(key)
+	if idx == -1 {
+		return n
+	}
+	removedLeaf.val = removedLeaf
+	if n.count == 1 {
+		return nil
+	}
+	editable := n.ensureEditable(edit)
+	last := 2 * (editable.count - 1)
+	editable.array[idx] = editable.array[last]
+	editable.array[idx+1] = editable.array[last+1]
+	editable.array[last] = nil
+	editable.array[last+1] = nil
+	editable.count--
+	return editable
+}
+
 func (n *HashCollisionNode) find(shift uint, hash uint32, key Object) *Pair {
 	idx := n.findIndex(key)
 	if idx == -1 {
@@ -540,6 +665,19 @@ func createNode(shift uint, key1 Object, val1 Object, key2hash uint32, key2 Obje
 	return emptyIndexedNode.assoc(shift, key1hash, key1, val1, addedLeaf).assoc(shift, key2hash, key2, val2, addedLeaf)
 }
 
+func editCreateNode(edit *Edit, shift uint, key1 Object, val1 Object, key2hash uint32, key2 Object, val2 Object) Node {
+	key1hash := key1.Hash()
+	if key1hash == key2hash {
+		return &HashCollisionNode{
+			hash:  key1hash,
+			count: 2,
+			array: []interface{}{key1, val1, key2, val2},
+		}
+	}
+	addedLeaf := &Box{}
+	return emptyIndexedNode.editAssoc(edit, shift, key1hash, key1, val1, addedLeaf).editAssoc(edit, shift, key2hash, key2, val2, addedLeaf)
+}
+
 func removePair(array []interface{}, n int) []interface{} {
 	newArray := make([]interface{}, len(array)-2)
 	for i := 0; i < 2*n; i++ {
@@ -668,6 +806,140 @@ func (b *BitmapIndexedNode) without(shift uint, hash uint32, key Object) Node {
 	return b
 }
 
+func (b *BitmapIndexedNode) ensureEditable(edit *Edit) *BitmapIndexedNode {
+	if b.edit == edit {
+		return b
+	}
+	n := bitCount(b.bitmap)
+	// Leave room for one more pair.
+	array := make([]interface{}, 2*(n+1))
+	copy(array, b.array[:2*n])
+	return &BitmapIndexedNode{
+		edit:   edit,
+		bitmap: b.bitmap,
+		array:  array,
+	}
+}
+
+func (b *BitmapIndexedNode) editAndSet(edit *Edit, i int, a interface{}) *BitmapIndexedNode {
+	editable := b.ensureEditable(edit)
+	editable.array[i] = a
+	return editable
+}
+
+func (b *BitmapIndexedNode) editAndRemovePair(edit *Edit, bit int, i int) Node {
+	if b.bitmap == bit {
+		return nil
+	}
+	editable := b.ensureEditable(edit)
+	editable.bitmap ^= bit
+	copy(editable.array[2*i:], editable.array[2*(i+1):])
+	editable.array[len(editable.array)-2] = nil
+	editable.array[len(editable.array)-1] = nil
+	return editable
+}
+
+func (b *BitmapIndexedNode) editAssoc(edit *Edit, shift uint, hash uint32, key Object, val Object, addedLeaf *Box) Node {
+	bit := bitpos(hash, shift)
+	idx := b.index(bit)
+	if b.bitmap&bit != 0 {
+		keyOrNull := b.array[2*idx]
+		valOrNode := b.array[2*idx+1]
+		if keyOrNull == nil {
+			n := valOrNode.(Node).editAssoc(edit, shift+5, hash, key, val, addedLeaf)
+			if n == valOrNode {
+				return b
+			}
+			return b.editAndSet(edit, 2*idx+1, n)
+		}
+		if key.Equals(keyOrNull) {
+			if val == valOrNode {
+				return b
+			}
+			return b.editAndSet(edit, 2*idx+1, val)
+		}
+		addedLeaf.val = addedLeaf
+		editable := b.ensureEditable(edit)
+		editable.array[2*idx] = nil
+		editable.array[2*idx+1] = editCreateNode(edit, shift+5, keyOrNull.(Object), valOrNode.(Object), hash, key, val)
+		return editable
+	}
+	n := bitCount(b.bitmap)
+	if n*2 < len(b.array) && b.edit == edit {
+		addedLeaf.val = addedLeaf
+		copy(b.array[2*(idx+1):], b.array[2*idx:2*n])
+		b.array[2*idx] = key
+		b.array[2*idx+1] = val
+		b.bitmap |= bit
+		return b
+	}
+	if n >= 16 {
+		nodes := make([]Node, 32)
+		jdx := mask(hash, shift)
+		nodes[jdx] = emptyIndexedNode.editAssoc(edit, shift+5, hash, key, val, addedLeaf)
+		j := 0
+		var i uint
+		for i = 0; i < 32; i++ {
+			if (b.bitmap>>i)&1 != 0 {
+				if b.array[j] == nil {
+					nodes[i] = b.array[j+1].(Node)
+				} else {
+					nodes[i] = emptyIndexedNode.editAssoc(edit, shift+5, b.array[j].(Object).Hash(), b.array[j].(Object), b.array[j+1].(Object), addedLeaf)
+				}
+				j += 2
+			}
+		}
+		return &ArrayNode{
+			edit:  edit,
+			count: n + 1,
+			array: nodes,
+		}
+	}
+	// Grow by more than one pair so that subsequent additions
+	// can be done in place.
+	array := make([]interface{}, 2*(n+4))
+	copy(array, b.array[:2*idx])
+	array[2*idx] = key
+	addedLeaf.val = addedLeaf
+	array[2*idx+1] = val
+	copy(array[2*(idx+1):], b.array[2*idx:2*n])
+	if b.edit == edit {
+		b.array = array
+		b.bitmap |= bit
+		return b
+	}
+	return &BitmapIndexedNode{
+		edit:   edit,
+		bitmap: b.bitmap | bit,
+		array:  array,
+	}
+}
+
+func (b *BitmapIndexedNode) editWithout(edit *Edit, shift uint, hash uint32, key Object, removedLeaf *Box) Node {
+	bit := bitpos(hash, shift)
+	if (b.bitmap & bit) == 0 {
+		return b
+	}
+	idx := b.index(bit)
+	keyOrNull := b.array[2*idx]
+	valOrNode := b.array[2*idx+1]
+	if keyOrNull == nil {
+		n := valOrNode.(Node).editWithout(edit, shift+5, hash, key, removedLeaf)
+		if n == valOrNode {
+			return b
+		}
+		if n != nil {
+			return b.editAndSet(edit, 2*idx+1, n)
+		}
+		return b.editAndRemovePair(edit, bit, idx)
+	}
+	if key.Equals(keyOrNull) {
+		removedLeaf.val = removedLeaf
+		return b.editAndRemovePair(edit, bit, idx)
+	}
+	return b
+}
+
 func (b *BitmapIndexedNode) find(shift uint, hash uint32, key Object) *Pair {
 	bit := bitpos(hash, shift)
 	if (b.bitmap & bit) == 0 {
@@ -860,11 +1132,5 @@ func (m *HashMap) Pprint(w io.Writer, indent int) int {
 }
 
 func (m *HashMap) kvreduce(c Callable, init Object) Object {
-	res := init
-	iter := m.Iter()
-	for iter.HasNext() {
-		kv := iter.Next()
-		res = c.Call([]Object{res, kv.Key, kv.Value})
-	}
-	return res
+	return kvreduceIter(m.Iter(), c, init)
 }
@@ -721,7 +721,7 @@ func checkInferredCall(call *CallExpr) bool {
 			}
 			passedValue := call.args[i].InferValue(newInferEnv())
 			if !passedValue.unknown && len(passedValue.types) != 0 && !inferredTypesCompatible(expectedTypes, passedValue.types) {
-				printParseWarning(call.args[i].Pos(), fmt.Sprintf("arg[%d] of %s must have type %s, got %s", i, call.Name(), inferredTypesString(expectedTypes), inferredTypesString(passedValue.types)))
+				printParseWarning(call.args[i].Pos(), "arg-type", fmt.Sprintf("arg[%d] of %s must have type %s, got %s", i, call.Name(), inferredTypesString(expectedTypes), inferredTypesString(passedValue.types)))
 				res = true
 			}
 		}
@@ -0,0 +1,141 @@
+package core
+
+import (
+	"fmt"
+	"runtime/metrics"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+type (
+	// InterruptedError is thrown when evaluation is interrupted: by
+	// Runtime.Interrupt, when the timeout set by Runtime.SetTimeout
+	// expires, when more steps than allowed by Runtime.SetMaxSteps
+	// have been taken or when the heap grows beyond the limit set by
+	// Runtime.SetMaxMemory.
+	InterruptedError struct {
+		EvalError
+	}
+	// limits holds the state used to interrupt evaluation.
+	limits struct {
+		steps     int64
+		maxSteps  int64
+		maxMemory uint64
+		interrupt int32 // accessed atomically
+		timeout   time.Duration
+		timer     *time.Timer
+	}
+)
+
+const (
+	noInterrupt int32 = iota
+	interruptRequested
+	interruptTimeout
+	interruptMemory
+)
+
+// The heap size is checked every memoryCheckInterval steps, as reading
+// it takes much longer than a step.
+const memoryCheckInterval = 1024
+
+const liveHeapMetric = "/gc/heap/live:bytes"
+
+// step is called wherever evaluation can be interrupted: function calls,
+// loop iterations and lazy seq realization. It panics with
+// InterruptedError if evaluation should stop. Once a limit has been
+// exceeded, every following step panics too, so catching the error
+// doesn't let the code run on.
+func (rt *Runtime) step() {
+	rt.limits.steps++
+	if rt.limits.maxSteps > 0 && rt.limits.steps > rt.limits.maxSteps {
+		panic(rt.newInterruptedError(fmt.Sprintf("Evaluation exceeded the maximum of %d steps", rt.limits.maxSteps)))
+	}
+	if rt.limits.maxMemory > 0 && rt.limits.steps%memoryCheckInterval == 0 && liveHeapSize() > rt.limits.maxMemory {
+		atomic.CompareAndSwapInt32(&rt.limits.interrupt, noInterrupt, interruptMemory)
+	}
+	switch atomic.LoadInt32(&rt.limits.interrupt) {
+	case noInterrupt:
+	case interruptTimeout:
+		panic(rt.newInterruptedError(fmt.Sprintf("Evaluation timed out after %s", rt.limits.timeout)))
+	case interruptMemory:
+		panic(rt.newInterruptedError(fmt.Sprintf("Evaluation exceeded the memory limit of %d bytes", rt.limits.maxMemory)))
+	default:
+		panic(rt.newInterruptedError("Evaluation interrupted"))
+	}
+}
+
+func (rt *Runtime) newInterruptedError(msg string) *InterruptedError {
+	return &InterruptedError{*rt.NewError(msg)}
+}
+
+// Interrupt makes evaluation throw InterruptedError at the next step.
+// It may be called from any goroutine.
+func (rt *Runtime) Interrupt() {
+	atomic.CompareAndSwapInt32(&rt.limits.interrupt, noInterrupt, interruptRequested)
+}
+
+// SetTimeout makes evaluation throw InterruptedError once d has
+// elapsed from now. 0 cancels the timeout.
+func (rt *Runtime) SetTimeout(d time.Duration) {
+	if rt.limits.timer != nil {
+		rt.limits.timer.Stop()
+		rt.limits.timer = nil
+	}
+	rt.limits.timeout = d
+	if d > 0 {
+		rt.limits.timer = time.AfterFunc(d, func() {
+			atomic.CompareAndSwapInt32(&rt.limits.interrupt, noInterrupt, interruptTimeout)
+		})
+	}
+}
+
+// SetMaxSteps limits evaluation to n more steps (function calls, loop
+// iterations and lazy seq realizations), after which it throws
+// InterruptedError. 0 means no limit.
+func (rt *Runtime) SetMaxSteps(n int64) {
+	rt.limits.steps = 0
+	rt.limits.maxSteps = n
+}
+
+// SetMaxMemory makes evaluation throw InterruptedError when the heap
+// grows beyond n bytes. What counts is the memory found to be in use by
+// the last garbage collection, so the heap can exceed n until the next
+// one. The heap is shared by the whole process: memory allocated by the
+// program embedding Joker counts too. 0 means no limit.
+func (rt *Runtime) SetMaxMemory(n uint64) {
+	rt.limits.maxMemory = n
+}
+
+func liveHeapSize() uint64 {
+	sample := []metrics.Sample{{Name: liveHeapMetric}}
+	metrics.Read(sample)
+	return sample[0].Value.Uint64()
+}
+
+// Steps returns the number of steps taken since the last call
+// to SetMaxSteps or ResetLimits.
+func (rt *Runtime) Steps() int64 {
+	return rt.limits.steps
+}
+
+// ResetLimits cancels the timeout, step and memory limits and clears a pending
+// interruption, so that the runtime can be used to evaluate more code.
+func (rt *Runtime) ResetLimits() {
+	rt.SetTimeout(0)
+	rt.SetMaxSteps(0)
+	rt.SetMaxMemory(0)
+	atomic.StoreInt32(&rt.limits.interrupt, noInterrupt)
+}
+
+func (err *InterruptedError) Equals(other interface{}) bool {
+	return err == other
+}
+
+func (err *InterruptedError) GetType() *Type {
+	return TYPE.InterruptedError
+}
+
+func (err *InterruptedError) Hash() uint32 {
+	return HashPtr(uintptr(unsafe.Pointer(err)))
+}
@@ -67,6 +67,9 @@ func (ns *Namespace) MaybeLazy(doc string) {
 		lazyFn := ns.Lazy
 		ns.Lazy = nil
 		lazyFn()
+		if SANDBOX != nil {
+			SANDBOX.apply(ns)
+		}
 		if VerbosityLevel > 0 {
 			fmt.Fprintf(Stderr, "NamespaceFor: Lazily initialized %s for %s\n", *ns.Name.name, doc)
 		}
@@ -114,7 +117,7 @@ func (ns *Namespace) Intern(sym Symbol) *Var {
 		if LINTER_TYPES[sym.name] {
 			msg := fmt.Sprintf("Expecting var, but %s is a type", *sym.name)
 			pos := sym.GetInfo().Pos()
-			printParseWarning(pos, msg)
+			printParseWarning(pos, "var-is-type", msg)
 		}
 	}
 	sym.meta = nil
@@ -135,7 +138,7 @@ func (ns *Namespace) Intern(sym Symbol) *Var {
 			}
 			ns.mappings[sym.name] = newVar
 			if !strings.HasPrefix(ns.Name.Name(), "joker.") {
-				printParseWarning(GetPosition(sym), fmt.Sprintf("WARNING: %s already refers to: %s in namespace %s, being replaced by: %s\n",
+				printParseWarning(GetPosition(sym), "core-var-replaced", fmt.Sprintf("WARNING: %s already refers to: %s in namespace %s, being replaced by: %s\n",
 					sym.ToString(false), existingVar.ToString(false), ns.Name.ToString(false), newVar.ToString(false)))
 			}
 			return newVar
@@ -146,9 +149,9 @@ func (ns *Namespace) Intern(sym Symbol) *Var {
 	if LINTER_MODE && existingVar.expr != nil && !existingVar.ns.Name.Equals(SYMBOLS.joker_core) {
 		if !isDeclaredInConfig(existingVar) {
 			if sym.GetInfo() == nil {
-				printParseWarning(existingVar.GetInfo().Pos(), "Subsequent duplicate def of "+existingVar.ToString(false))
+				printParseWarning(existingVar.GetInfo().Pos(), "duplicate-def", "Subsequent duplicate def of "+existingVar.ToString(false))
 			} else {
-				printParseWarning(sym.GetInfo().Pos(), "Duplicate def of "+existingVar.ToString(false))
+				printParseWarning(sym.GetInfo().Pos(), "duplicate-def", "Duplicate def of "+existingVar.ToString(false))
 			}
 		}
 	}
@@ -189,7 +192,7 @@ func (ns *Namespace) AddAlias(alias Symbol, namespace *Namespace) {
 	if existing != nil && existing != namespace {
 		msg := "Alias " + alias.ToString(false) + " already exists in namespace " + ns.Name.ToString(false) + ", aliasing " + existing.Name.ToString(false)
 		if LINTER_MODE {
-			printParseError(GetPosition(alias), msg)
+			printParseError(GetPosition(alias), "duplicate-alias", msg)
 			return
 		}
 		panic(RT.NewError(msg))
@@ -32,28 +32,31 @@ type (
 		Quotient(Number, Number) Number
 		Rem(Number, Number) Number
 	}
-	IntOps      struct{}
-	DoubleOps   struct{}
-	BigIntOps   struct{}
-	BigFloatOps struct{}
-	RatioOps    struct{}
+	IntOps        struct{}
+	DoubleOps     struct{}
+	BigIntOps     struct{}
+	BigFloatOps   struct{}
+	RatioOps      struct{}
+	BigDecimalOps struct{}
 )
 
 const (
 	INTEGER_CATEGORY  = iota
 	FLOATING_CATEGORY = iota
 	RATIO_CATEGORY    = iota
+	DECIMAL_CATEGORY  = iota
 )
 
 const MAX_RUNE = int(^uint32(0) >> 1)
 const MIN_RUNE = -MAX_RUNE - 1
 
 var (
-	INT_OPS      = IntOps{}
-	DOUBLE_OPS   = DoubleOps{}
-	BIGINT_OPS   = BigIntOps{}
-	BIGFLOAT_OPS = BigFloatOps{}
-	RATIO_OPS    = RatioOps{}
+	INT_OPS        = IntOps{}
+	DOUBLE_OPS     = DoubleOps{}
+	BIGINT_OPS     = BigIntOps{}
+	BIGFLOAT_OPS   = BigFloatOps{}
+	RATIO_OPS      = RatioOps{}
+	BIGDECIMAL_OPS = BigDecimalOps{}
 )
 
 func ratioOrInt(r *big.Rat) Number {
@@ -106,7 +109,7 @@ func (ops BigFloatOps) Combine(other Ops) Ops {
 
 func (ops RatioOps) Combine(other Ops) Ops {
 	switch other.(type) {
-	case DoubleOps, BigFloatOps:
+	case DoubleOps, BigFloatOps, BigDecimalOps:
 		return other
 	default:
 		return ops
@@ -123,6 +126,8 @@ func GetOps(obj Object) Ops {
 		return BIGFLOAT_OPS
 	case *Ratio:
 		return RATIO_OPS
+	case *BigDecimal:
+		return BIGDECIMAL_OPS
 	default:
 		return INT_OPS
 	}
@@ -655,6 +660,8 @@ func category(x Number) int {
 		return FLOATING_CATEGORY
 	case *Ratio:
 		return RATIO_CATEGORY
+	case *BigDecimal:
+		return DECIMAL_CATEGORY
 	default:
 		return INTEGER_CATEGORY
 	}
@@ -1,5 +1,5 @@
-//go:generate go run gen/gen_types.go assert Comparable Vec Char String Symbol Keyword *Regex Boolean Time Number Seqable Callable *Type Meta Int Double Stack Map Set Associative Reversible Named Comparator *Ratio *BigFloat *BigInt *Namespace *Var Error *Fn Deref *Atom Ref KVReduce Reduce Pending *File io.Reader io.Writer StringReader io.RuneReader *Channel CountedIndexed
-//go:generate go run gen/gen_types.go info *List *ArrayMapSeq *ArrayMap *HashMap *ExInfo *Fn *Var Nil *Ratio *BigInt *BigFloat Char Double Int Boolean Time Keyword *Regex Symbol String Comment *LazySeq *MappingSeq *ArraySeq *ConsSeq *NodeSeq *ArrayNodeSeq *MapSet *Vector *ArrayVector *VectorSeq *VectorRSeq
+//go:generate go run gen/gen_types.go assert Comparable Vec Char String Symbol Keyword *Regex Boolean Time Number Seqable Callable *Type Meta Int Double Stack Map Set Associative Reversible Named Comparator *Ratio *BigFloat *BigDecimal *BigInt *Namespace *Var Error *Fn Deref *Atom Ref KVReduce Reduce Pending *File io.Reader io.Writer StringReader io.RuneReader *Channel CountedIndexed Transient Sorted *Volatile BlockingDeref *Future *Promise *ChannelBuffer *Agent Watchable *Bytes UUID
+//go:generate go run gen/gen_types.go info *List *ArrayMapSeq *ArrayMap *HashMap *ExInfo *Fn *Var Nil *Ratio *BigInt *BigFloat *BigDecimal Char Double Int Boolean Time Keyword *Regex Symbol String Comment *LazySeq *MappingSeq *ArraySeq *ConsSeq *NodeSeq *ArrayNodeSeq *MapSet *Vector *ArrayVector *VectorSeq *VectorRSeq *Record *SortedMap *SortedMapSeq *SortedSet *Bytes UUID *TaggedLiteral
 //go:generate go run -tags gen_code gen_code/gen_code.go
 
 package core
@@ -39,6 +39,7 @@ type (
 		MetaHolder
 		name        string
 		reflectType reflect.Type
+		user        *userType
 	}
 	Object interface {
 		Equality
@@ -151,6 +152,7 @@ type (
 	Var struct {
 		InfoHolder
 		MetaHolder
+		WatchHolder
 		ns             *Namespace
 		name           Symbol
 		Value          Object
@@ -239,6 +241,7 @@ type (
 	}
 	Atom struct {
 		MetaHolder
+		WatchHolder
 		value Object
 	}
 	Deref interface {
@@ -247,6 +250,11 @@ type (
 	Native interface {
 		Native() interface{}
 	}
+	// Closer is implemented by objects holding resources that
+	// with-open and joker.io/close can release.
+	Closer interface {
+		Close() error
+	}
 	KVReduce interface {
 		kvreduce(c Callable, init Object) Object
 	}
@@ -258,79 +266,106 @@ type (
 		IsRealized() bool
 	}
 	Types struct {
-		Associative    *Type
-		Callable       *Type
-		Collection     *Type
-		Comparable     *Type
-		Comparator     *Type
-		Counted        *Type
-		CountedIndexed *Type
-		Deref          *Type
-		Channel        *Type
-		Error          *Type
-		Gettable       *Type
-		Indexed        *Type
-		IOReader       *Type
-		IOWriter       *Type
-		KVReduce       *Type
-		Reduce         *Type
-		Map            *Type
-		Meta           *Type
-		Named          *Type
-		Number         *Type
-		Pending        *Type
-		Ref            *Type
-		Reversible     *Type
-		Seq            *Type
-		Seqable        *Type
-		Sequential     *Type
-		Set            *Type
-		Stack          *Type
-		ArrayMap       *Type
-		ArrayMapSeq    *Type
-		ArrayNodeSeq   *Type
-		ArraySeq       *Type
-		MapSet         *Type
-		Atom           *Type
-		BigFloat       *Type
-		BigInt         *Type
-		Boolean        *Type
-		Time           *Type
-		Buffer         *Type
-		Char           *Type
-		ConsSeq        *Type
-		Delay          *Type
-		Double         *Type
-		EvalError      *Type
-		ExInfo         *Type
-		Fn             *Type
-		File           *Type
-		BufferedReader *Type
-		HashMap        *Type
-		Int            *Type
-		Keyword        *Type
-		LazySeq        *Type
-		List           *Type
-		MappingSeq     *Type
-		Namespace      *Type
-		Nil            *Type
-		NodeSeq        *Type
-		ParseError     *Type
-		Proc           *Type
-		ProcFn         *Type
-		Ratio          *Type
-		RecurBindings  *Type
-		Regex          *Type
-		String         *Type
-		Symbol         *Type
-		Type           *Type
-		Var            *Type
-		Vector         *Type
-		Vec            *Type
-		ArrayVector    *Type
-		VectorRSeq     *Type
-		VectorSeq      *Type
-		StringSeq      *Type
+		Associative        *Type
+		Callable           *Type
+		Closer             *Type
+		Collection         *Type
+		Comparable         *Type
+		Comparator         *Type
+		Counted            *Type
+		CountedIndexed     *Type
+		Deref              *Type
+		Channel            *Type
+		Error              *Type
+		Gettable           *Type
+		Indexed            *Type
+		IOReader           *Type
+		IOWriter           *Type
+		KVReduce           *Type
+		Reduce             *Type
+		Map                *Type
+		Meta               *Type
+		Named              *Type
+		Number             *Type
+		Object             *Type
+		Pending            *Type
+		Ref                *Type
+		Reversible         *Type
+		Seq                *Type
+		Seqable            *Type
+		Sequential         *Type
+		Set                *Type
+		Sorted             *Type
+		Stack              *Type
+		Transient          *Type
+		ArrayMap           *Type
+		ArrayMapSeq        *Type
+		ArrayNodeSeq       *Type
+		ArraySeq           *Type
+		MapSet             *Type
+		Atom               *Type
+		BigDecimal         *Type
+		BigFloat           *Type
+		BigInt             *Type
+		Boolean            *Type
+		Bytes              *Type
+		BytesSeq           *Type
+		Time               *Type
+		Buffer             *Type
+		Char               *Type
+		ConsSeq            *Type
+		Delay              *Type
+		Double             *Type
+		EvalError          *Type
+		ExInfo             *Type
+		Fn                 *Type
+		File               *Type
+		BufferedReader     *Type
+		HashMap            *Type
+		Int                *Type
+		InterruptedError   *Type
+		Keyword            *Type
+		LazySeq            *Type
+		List               *Type
+		MappingSeq         *Type
+		Namespace          *Type
+		Nil                *Type
+		NodeSeq            *Type
+		ParseError         *Type
+		Proc               *Type
+		ProcFn             *Type
+		Ratio              *Type
+		Record             *Type
+		RecurBindings      *Type
+		Regex              *Type
+		String             *Type
+		Symbol             *Type
+		TaggedLiteral      *Type
+		Type               *Type
+		UUID               *Type
+		Var                *Type
+		Vector             *Type
+		Vec                *Type
+		ArrayVector        *Type
+		VectorRSeq         *Type
+		VectorSeq          *Type
+		StringSeq          *Type
+		TransientVector    *Type
+		TransientArrayMap  *Type
+		TransientHashMap   *Type
+		TransientSet       *Type
+		SortedMap          *Type
+		SortedMapSeq       *Type
+		SortedSet          *Type
+		StackOverflowError *Type
+		Reduced            *Type
+		Volatile           *Type
+		BlockingDeref      *Type
+		Future             *Type
+		Promise            *Type
+		ChannelBuffer      *Type
+		Agent              *Type
+		Watchable          *Type
 	}
 )
 
@@ -347,6 +382,16 @@ func (pos Position) Filename() string {
 	return *pos.filename
 }
 
+// Start returns the (1-based) line and column where pos starts.
+func (pos Position) Start() (line, column int) {
+	return pos.startLine, pos.startColumn
+}
+
+// End returns the (1-based) line and column of the last character in pos.
+func (pos Position) End() (line, column int) {
+	return pos.endLine, pos.endColumn
+}
+
 func newIteratorError() error {
 	return errors.New("Iterator reached the end of collection")
 }
@@ -453,7 +498,7 @@ func CheckArity(args []Object, min int, max int) {
 func getMap(k Object, args []Object) Object {
 	CheckArity(args, 1, 2)
 	switch m := args[0].(type) {
-	case Map:
+	case Gettable:
 		ok, v := m.Get(k)
 		if ok {
 			return v
@@ -838,6 +883,14 @@ func (v *Var) Name() string {
 	return v.ns.Name.ToString(false) + "/" + v.name.ToString(false)
 }
 
+func (v *Var) Namespace() *Namespace {
+	return v.ns
+}
+
+func (v *Var) Symbol() Symbol {
+	return v.name
+}
+
 func (v *Var) ToString(escape bool) string {
 	return "#'" + v.Name()
 }
@@ -1330,6 +1383,9 @@ func (b Boolean) Compare(other Object) int {
 }
 
 func (t Time) ToString(escape bool) string {
+	if escape {
+		return "#inst \"" + t.T.Format(time.RFC3339Nano) + "\""
+	}
 	return t.T.String()
 }
 
@@ -1682,7 +1738,12 @@ func (x RecurBindings) WithInfo(info *ObjectInfo) Object {
 
 func IsEqualOrImplements(abstractType *Type, concreteType *Type) bool {
 	if abstractType.reflectType.Kind() == reflect.Interface {
+		if concreteType.user != nil {
+			return concreteType.user.implements(abstractType, concreteType)
+		}
 		return concreteType.reflectType.Implements(abstractType.reflectType)
+	} else if abstractType.user != nil {
+		return concreteType == abstractType
 	} else {
 		return concreteType.reflectType == abstractType.reflectType
 	}
@@ -1720,7 +1781,7 @@ func RegRefType(name string, inst interface{}, doc string) *Type {
 	}
 	meta := MakeMeta(nil, "(Concrete reference type)"+doc, "1.0")
 	meta.Add(KEYWORDS.name, MakeString(name))
-	t := &Type{MetaHolder{meta}, name, reflect.TypeOf(inst)}
+	t := &Type{MetaHolder: MetaHolder{meta}, name: name, reflectType: reflect.TypeOf(inst)}
 	TYPES[STRINGS.Intern(name)] = t
 	return t
 }
@@ -1731,7 +1792,7 @@ func RegType(name string, inst interface{}, doc string) *Type {
 	}
 	meta := MakeMeta(nil, "(Concrete type)"+doc, "1.0")
 	meta.Add(KEYWORDS.name, MakeString(name))
-	t := &Type{MetaHolder{meta}, name, reflect.TypeOf(inst).Elem()}
+	t := &Type{MetaHolder: MetaHolder{meta}, name: name, reflectType: reflect.TypeOf(inst).Elem()}
 	TYPES[STRINGS.Intern(name)] = t
 	return t
 }
@@ -1742,7 +1803,7 @@ func RegInterface(name string, inst interface{}, doc string) *Type {
 	}
 	meta := MakeMeta(nil, "(Interface type)"+doc, "1.0")
 	meta.Add(KEYWORDS.name, MakeString(name))
-	t := &Type{MetaHolder{meta}, name, reflect.TypeOf(inst).Elem()}
+	t := &Type{MetaHolder: MetaHolder{meta}, name: name, reflectType: reflect.TypeOf(inst).Elem()}
 	TYPES[STRINGS.Intern(name)] = t
 	return t
 }
@@ -1812,6 +1873,9 @@ func CountedIndexedKvreduce(v CountedIndexed, c Callable, init Object) Object {
 	res := init
 	for i := 0; i < v.Count(); i++ {
 		res = c.Call([]Object{res, Int{I: i}, v.At(i)})
+		if r, ok := res.(*Reduced); ok {
+			return r.val
+		}
 	}
 	return res
 }
@@ -1860,33 +1924,23 @@ func CountedIndexedReduce(v CountedIndexed, c Callable) Object {
 	case 1:
 		return v.At(0)
 	default:
-		args := make([]Object, 2)
-		args[0] = v.At(0)
-		args[1] = v.At(1)
-		acc := c.Call(args)
-		for i := 2; i < v.Count(); i++ {
-			args[0] = acc
-			args[1] = v.At(i)
-			acc = c.Call(args)
-		}
-		return acc
+		return countedIndexedReduceFrom(v, c, v.At(0), 1)
 	}
 }
 
 func CountedIndexedReduceInit(v CountedIndexed, c Callable, init Object) Object {
-	switch v.Count() {
-	case 0:
-		return init
-	default:
-		args := make([]Object, 2)
-		args[0] = init
-		args[1] = v.At(0)
-		acc := c.Call(args)
-		for i := 1; i < v.Count(); i++ {
-			args[0] = acc
-			args[1] = v.At(i)
-			acc = c.Call(args)
+	return countedIndexedReduceFrom(v, c, init, 0)
+}
+
+// countedIndexedReduceFrom allocates fresh args for every call,
+// since functions may close over their arguments.
+func countedIndexedReduceFrom(v CountedIndexed, c Callable, init Object, start int) Object {
+	acc := init
+	for i := start; i < v.Count(); i++ {
+		acc = c.Call([]Object{acc, v.At(i)})
+		if r, ok := acc.(*Reduced); ok {
+			return r.val
 		}
-		return acc
 	}
+	return acc
 }
@@ -15,6 +15,7 @@ func init() {
 	TYPE = Types{
 		Associative:    RegInterface("Associative", (*Associative)(nil), ""),
 		Callable:       RegInterface("Callable", (*Callable)(nil), ""),
+		Closer:         RegInterface("Closer", (*Closer)(nil), "Resource that can be closed, e.g. by with-open"),
 		Collection:     RegInterface("Collection", (*Collection)(nil), ""),
 		Comparable:     RegInterface("Comparable", (*Comparable)(nil), ""),
 		Comparator:     RegInterface("Comparator", (*Comparator)(nil), ""),
@@ -32,6 +33,7 @@ func init() {
 		Meta:           RegInterface("Meta", (*Meta)(nil), ""),
 		Named:          RegInterface("Named", (*Named)(nil), ""),
 		Number:         RegInterface("Number", (*Number)(nil), ""),
+		Object:         RegInterface("Object", (*Object)(nil), ""),
 		Pending:        RegInterface("Pending", (*Pending)(nil), ""),
 		Ref:            RegInterface("Ref", (*Ref)(nil), ""),
 		Reversible:     RegInterface("Reversible", (*Reversible)(nil), ""),
@@ -39,15 +41,20 @@ func init() {
 		Seqable:        RegInterface("Seqable", (*Seqable)(nil), ""),
 		Sequential:     RegInterface("Sequential", (*Sequential)(nil), ""),
 		Set:            RegInterface("Set", (*Set)(nil), ""),
+		Sorted:         RegInterface("Sorted", (*Sorted)(nil), ""),
 		Stack:          RegInterface("Stack", (*Stack)(nil), ""),
+		Transient:      RegInterface("Transient", (*Transient)(nil), ""),
 		ArrayMap:       RegRefType("ArrayMap", (*ArrayMap)(nil), ""),
 		ArrayMapSeq:    RegRefType("ArrayMapSeq", (*ArrayMapSeq)(nil), ""),
 		ArrayNodeSeq:   RegRefType("ArrayNodeSeq", (*ArrayNodeSeq)(nil), ""),
 		ArraySeq:       RegRefType("ArraySeq", (*ArraySeq)(nil), ""),
 		MapSet:         RegRefType("MapSet", (*MapSet)(nil), ""),
 		Atom:           RegRefType("Atom", (*Atom)(nil), ""),
+		BigDecimal:     RegRefType("BigDecimal", (*BigDecimal)(nil), "Arbitrary-precision decimal number"),
 		BigFloat:       RegRefType("BigFloat", (*BigFloat)(nil), "Wraps the Go 'math/big.Float' type"),
 		BigInt:         RegRefType("BigInt", (*BigInt)(nil), "Wraps the Go 'math/big.Int' type"),
+		Bytes:          RegRefType("Bytes", (*Bytes)(nil), "Immutable sequence of bytes"),
+		BytesSeq:       RegRefType("BytesSeq", (*bytesSeq)(nil), ""),
 		Boolean:        RegType("Boolean", (*Boolean)(nil), "Wraps the Go 'bool' type"),
 		Time:           RegType("Time", (*Time)(nil), "Wraps the Go 'time.Time' type"),
 		Buffer:         RegRefType("Buffer", (*Buffer)(nil), ""),
@@ -57,6 +64,8 @@ func init() {
 		Channel:        RegRefType("Channel", (*Channel)(nil), ""),
 		Double:         RegType("Double", (*Double)(nil), "Wraps the Go 'float64' type"),
 		EvalError:      RegRefType("EvalError", (*EvalError)(nil), ""),
+		StackOverflowError: RegRefType("StackOverflowError", (*StackOverflowError)(nil),
+			"Thrown when the call stack gets deeper than *max-call-depth*"),
 		ExInfo:         RegRefType("ExInfo", (*ExInfo)(nil), ""),
 		Fn:             RegRefType("Fn", (*Fn)(nil), "A callable function or macro implemented via Joker code"),
 		File:           RegRefType("File", (*File)(nil), ""),
@@ -64,27 +73,47 @@ func init() {
 		HashMap:        RegRefType("HashMap", (*HashMap)(nil), ""),
 		Int: RegType("Int", (*Int)(nil),
 			"Wraps the Go 'int' type, which is 32 bits wide on 32-bit hosts, 64 bits wide on 64-bit hosts, etc."),
-		Keyword:       RegType("Keyword", (*Keyword)(nil), "A possibly-namespace-qualified name prefixed by ':'"),
-		LazySeq:       RegRefType("LazySeq", (*LazySeq)(nil), ""),
-		List:          RegRefType("List", (*List)(nil), ""),
-		MappingSeq:    RegRefType("MappingSeq", (*MappingSeq)(nil), ""),
-		Namespace:     RegRefType("Namespace", (*Namespace)(nil), ""),
-		Nil:           RegType("Nil", (*Nil)(nil), "The 'nil' value"),
-		NodeSeq:       RegRefType("NodeSeq", (*NodeSeq)(nil), ""),
-		ParseError:    RegRefType("ParseError", (*ParseError)(nil), ""),
-		Proc:          RegRefType("Proc", (*Proc)(nil), "A callable function implemented via Go code"),
-		Ratio:         RegRefType("Ratio", (*Ratio)(nil), "Wraps the Go 'math.big/Rat' type"),
-		RecurBindings: RegRefType("RecurBindings", (*RecurBindings)(nil), ""),
-		Regex:         RegRefType("Regex", (*Regex)(nil), "Wraps the Go 'regexp.Regexp' type"),
-		String:        RegType("String", (*String)(nil), "Wraps the Go 'string' type"),
-		Symbol:        RegType("Symbol", (*Symbol)(nil), ""),
-		Type:          RegRefType("Type", (*Type)(nil), ""),
-		Var:           RegRefType("Var", (*Var)(nil), ""),
-		Vector:        RegRefType("Vector", (*Vector)(nil), ""),
-		Vec:           RegInterface("Vec", (*Vec)(nil), ""),
-		ArrayVector:   RegRefType("ArrayVector", (*ArrayVector)(nil), ""),
-		VectorRSeq:    RegRefType("VectorRSeq", (*VectorRSeq)(nil), ""),
-		VectorSeq:     RegRefType("VectorSeq", (*VectorSeq)(nil), ""),
-		StringSeq:     RegRefType("StringSeq", (*stringSeq)(nil), ""),
+		InterruptedError: RegRefType("InterruptedError", (*InterruptedError)(nil),
+			"Thrown when evaluation is interrupted, e.g. by --timeout or --max-steps"),
+		Keyword:           RegType("Keyword", (*Keyword)(nil), "A possibly-namespace-qualified name prefixed by ':'"),
+		LazySeq:           RegRefType("LazySeq", (*LazySeq)(nil), ""),
+		List:              RegRefType("List", (*List)(nil), ""),
+		MappingSeq:        RegRefType("MappingSeq", (*MappingSeq)(nil), ""),
+		Namespace:         RegRefType("Namespace", (*Namespace)(nil), ""),
+		Nil:               RegType("Nil", (*Nil)(nil), "The 'nil' value"),
+		NodeSeq:           RegRefType("NodeSeq", (*NodeSeq)(nil), ""),
+		ParseError:        RegRefType("ParseError", (*ParseError)(nil), ""),
+		Proc:              RegRefType("Proc", (*Proc)(nil), "A callable function implemented via Go code"),
+		Ratio:             RegRefType("Ratio", (*Ratio)(nil), "Wraps the Go 'math.big/Rat' type"),
+		Record:            RegRefType("Record", (*Record)(nil), "A record type instance created by defrecord"),
+		RecurBindings:     RegRefType("RecurBindings", (*RecurBindings)(nil), ""),
+		Regex:             RegRefType("Regex", (*Regex)(nil), "Wraps the Go 'regexp.Regexp' type"),
+		String:            RegType("String", (*String)(nil), "Wraps the Go 'string' type"),
+		Symbol:            RegType("Symbol", (*Symbol)(nil), ""),
+		TaggedLiteral:     RegRefType("TaggedLiteral", (*TaggedLiteral)(nil), "A tagged literal with no reader function, created by the reader or tagged-literal"),
+		Type:              RegRefType("Type", (*Type)(nil), ""),
+		UUID:              RegType("UUID", (*UUID)(nil), "A universally unique identifier, read and printed as #uuid"),
+		Var:               RegRefType("Var", (*Var)(nil), ""),
+		Vector:            RegRefType("Vector", (*Vector)(nil), ""),
+		Vec:               RegInterface("Vec", (*Vec)(nil), ""),
+		ArrayVector:       RegRefType("ArrayVector", (*ArrayVector)(nil), ""),
+		VectorRSeq:        RegRefType("VectorRSeq", (*VectorRSeq)(nil), ""),
+		VectorSeq:         RegRefType("VectorSeq", (*VectorSeq)(nil), ""),
+		StringSeq:         RegRefType("StringSeq", (*stringSeq)(nil), ""),
+		TransientVector:   RegRefType("TransientVector", (*TransientVector)(nil), "A transient vector created by transient"),
+		TransientArrayMap: RegRefType("TransientArrayMap", (*TransientArrayMap)(nil), "A transient array map created by transient"),
+		TransientHashMap:  RegRefType("TransientHashMap", (*TransientHashMap)(nil), "A transient hash map created by transient"),
+		TransientSet:      RegRefType("TransientSet", (*TransientSet)(nil), "A transient set created by transient"),
+		SortedMap:         RegRefType("SortedMap", (*SortedMap)(nil), "A persistent map sorted by keys, created by sorted-map"),
+		SortedMapSeq:      RegRefType("SortedMapSeq", (*SortedMapSeq)(nil), ""),
+		SortedSet:         RegRefType("SortedSet", (*SortedSet)(nil), "A persistent sorted set created by sorted-set"),
+		Reduced:           RegRefType("Reduced", (*Reduced)(nil), "A wrapper created by reduced that stops a reduction early"),
+		Volatile:          RegRefType("Volatile", (*Volatile)(nil), "A mutable box created by volatile!"),
+		BlockingDeref:     RegInterface("BlockingDeref", (*BlockingDeref)(nil), ""),
+		Future:            RegRefType("Future", (*Future)(nil), "A reference to the result of a body run in a goroutine, created by future"),
+		Promise:           RegRefType("Promise", (*Promise)(nil), "A reference that can be delivered a value once, created by promise"),
+		ChannelBuffer:     RegRefType("ChannelBuffer", (*ChannelBuffer)(nil), "A channel buffer created by buffer, sliding-buffer or dropping-buffer"),
+		Agent:             RegRefType("Agent", (*Agent)(nil), "A reference whose value is changed asynchronously by actions, created by agent"),
+		Watchable:         RegInterface("Watchable", (*Watchable)(nil), "A reference that supports watches and validators: an atom, var or agent"),
 	}
 }
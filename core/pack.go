@@ -35,6 +35,8 @@ const (
 	SYMBOL_OBJ     = 102
 	VAR_OBJ        = 103
 	TYPE_OBJ       = 104
+	SORTED_MAP_OBJ = 105
+	SORTED_SET_OBJ = 106
 )
 
 type (
@@ -324,6 +326,40 @@ func unpackType(p []byte, header *PackHeader) (*Type, []byte) {
 	return TYPES[s.name], p
 }
 
+// Sorted collections are packed element by element, since printing
+// them would read back as hash maps and sets. Only the default
+// ordering can be packed.
+func (m *SortedMap) Pack(p []byte, env *PackEnv) []byte {
+	if m.comp != nil {
+		panic(RT.NewError("Cannot pack sorted collection with a custom comparator"))
+	}
+	p = PackObjectOrNull(m.meta, p, env)
+	p = appendInt(p, m.count)
+	for iter := m.Iter(); iter.HasNext(); {
+		kv := iter.Next()
+		p = packObject(kv.Key, p, env)
+		p = packObject(kv.Value, p, env)
+	}
+	return p
+}
+
+func unpackSortedMap(p []byte, header *PackHeader) (*SortedMap, []byte) {
+	meta, p := UnpackObjectOrNull(p, header)
+	count, p := extractInt(p)
+	var res Associative = NewSortedMap(nil)
+	for i := 0; i < count; i++ {
+		var key, val Object
+		key, p = unpackObject(p, header)
+		val, p = unpackObject(p, header)
+		res = res.Assoc(key, val)
+	}
+	m := res.(*SortedMap)
+	if meta != nil {
+		m.meta = meta.(Map)
+	}
+	return m, p
+}
+
 func packObject(obj Object, p []byte, env *PackEnv) []byte {
 	switch obj := obj.(type) {
 	case Symbol:
@@ -337,6 +373,14 @@ func packObject(obj Object, p []byte, env *PackEnv) []byte {
 		p = append(p, TYPE_OBJ)
 		p = obj.Pack(p, env)
 		return p
+	case *SortedMap:
+		p = append(p, SORTED_MAP_OBJ)
+		return obj.Pack(p, env)
+	case *SortedSet:
+		p = append(p, SORTED_SET_OBJ)
+		m := *obj.m
+		m.meta = obj.meta
+		return m.Pack(p, env)
 	default:
 		p = append(p, NULL)
 		var buf bytes.Buffer
@@ -356,6 +400,14 @@ func unpackObject(p []byte, header *PackHeader) (Object, []byte) {
 		return unpackVar(p[1:], header)
 	case TYPE_OBJ:
 		return unpackType(p[1:], header)
+	case SORTED_MAP_OBJ:
+		return unpackSortedMap(p[1:], header)
+	case SORTED_SET_OBJ:
+		m, p := unpackSortedMap(p[1:], header)
+		res := &SortedSet{m: m}
+		res.meta = m.meta
+		m.meta = nil
+		return res, p
 	case NULL:
 		var size int
 		size, p = extractInt(p[1:])
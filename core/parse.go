@@ -53,6 +53,7 @@ type (
 		Position
 		callable Expr
 		args     []Expr
+		debug    *debugInfo // only set when the debugger is enabled
 	}
 	MacroCallExpr struct {
 		Position
@@ -161,6 +162,7 @@ type (
 		noRecurAllowed         bool
 		isUnknownCallableScope bool
 		isLinterFile           bool
+		macroDepth             int
 	}
 	Warnings struct {
 		ifWithoutElse           bool
@@ -169,6 +171,7 @@ type (
 		ignoredUnusedNamespaces Set
 		IgnoredFileRegexes      []*regexp.Regexp
 		entryPoints             Set
+		levels                  map[string]string
 	}
 	Keywords struct {
 		tag                Keyword
@@ -192,7 +195,14 @@ type (
 		ifWithoutElse      Keyword
 		unusedFnParameters Keyword
 		fnWithEmptyBody    Keyword
+		linters            Keyword
+		level              Keyword
+		off                Keyword
+		warning            Keyword
+		error              Keyword
+		jokerIgnore        Keyword
 		_prefix            Keyword
+		_code              Keyword
 		pos                Keyword
 		startLine          Keyword
 		endLine            Keyword
@@ -219,6 +229,8 @@ type (
 		ascii              Keyword
 		unicode            Keyword
 		any                Keyword
+		default_           Keyword
+		methodTable        Keyword
 	}
 	Symbols struct {
 		joker_core         Symbol
@@ -255,6 +267,8 @@ type (
 		hashMap            Symbol
 		hashSet            Symbol
 		defaultDataReaders Symbol
+		printMethod        Symbol
+		printDup           Symbol
 		backslash          Symbol
 		deref              Symbol
 		ns                 Symbol
@@ -381,7 +395,7 @@ func (b *Bindings) AddBinding(sym Symbol, index int, skipUnused bool) *Binding {
 	if LINTER_MODE && !skipUnused {
 		old := b.bindings[sym.name]
 		if old != nil && needsUnusedWarning(old) {
-			printParseWarning(GetPosition(old.name), "Unused binding: "+old.name.ToString(false))
+			printParseWarning(GetPosition(old.name), "unused-binding", "Unused binding: "+old.name.ToString(false))
 		}
 	}
 	binding := &Binding{
@@ -432,35 +446,32 @@ func (pos Position) Pos() Position {
 	return pos
 }
 
-func printError(pos Position, msg string) {
-	PROBLEM_COUNT++
-	fmt.Fprintf(Stderr, "%s:%d:%d: %s\n", pos.Filename(), pos.startLine, pos.startColumn, msg)
+func printError(pos Position, kind, code, msg string) {
+	reportLinterProblem(Problem{Position: pos, Kind: kind, Code: code, Message: msg})
 }
 
-func printParseWarning(pos Position, msg string) {
-	printError(pos, "Parse warning: "+msg)
+func printParseWarning(pos Position, code, msg string) {
+	printError(pos, "Parse warning", code, msg)
 }
 
-func printParseError(pos Position, msg string) {
-	printError(pos, "Parse error: "+msg)
+func printParseError(pos Position, code, msg string) {
+	printError(pos, "Parse error", code, msg)
 }
 
-func printReadWarning(reader *Reader, msg string) {
-	pos := Position{
+func readerPosition(reader *Reader) Position {
+	return Position{
 		filename:    reader.filename,
 		startColumn: reader.column,
 		startLine:   reader.line,
 	}
-	printError(pos, "Read warning: "+msg)
 }
 
-func printReadError(reader *Reader, msg string) {
-	pos := Position{
-		filename:    reader.filename,
-		startColumn: reader.column,
-		startLine:   reader.line,
-	}
-	printError(pos, "Read error: "+msg)
+func printReadWarning(reader *Reader, code, msg string) {
+	printError(readerPosition(reader), "Read warning", code, msg)
+}
+
+func printReadError(reader *Reader, code, msg string) {
+	printError(readerPosition(reader), "Read error", code, msg)
 }
 
 func isIgnoredUnusedNamespace(ns *Namespace) bool {
@@ -505,7 +516,7 @@ func WarnOnGloballyUnusedNamespaces() {
 
 	sort.Strings(names)
 	for _, name := range names {
-		printParseWarning(positions[name], "globally unused namespace "+name)
+		printParseWarning(positions[name], "globally-unused-namespace", "globally unused namespace "+name)
 	}
 }
 
@@ -526,7 +537,7 @@ func WarnOnUnusedNamespaces() {
 
 	sort.Strings(names)
 	for _, name := range names {
-		printParseWarning(positions[name], "unused namespace "+name)
+		printParseWarning(positions[name], "unused-namespace", "unused namespace "+name)
 	}
 }
 
@@ -564,7 +575,7 @@ func WarnOnGloballyUnusedVars() {
 
 	sort.Strings(names)
 	for _, name := range names {
-		printParseWarning(positions[name], "globally unused var "+name)
+		printParseWarning(positions[name], "globally-unused-var", "globally unused var "+name)
 	}
 }
 
@@ -589,7 +600,7 @@ func WarnOnUnusedVars() {
 
 	sort.Strings(names)
 	for _, name := range names {
-		printParseWarning(positions[name], "unused var "+name)
+		printParseWarning(positions[name], "unused-var", "unused var "+name)
 	}
 }
 
@@ -743,7 +754,7 @@ func checkReturnType(vr *Var, valueExpr Expr) {
 		returnExpr := arity.body[len(arity.body)-1]
 		returnedValue := returnExpr.InferValue(newInferEnv())
 		if !returnedValue.unknown && len(returnedValue.types) != 0 && !inferredTypesCompatible(vr.taggedTypes, returnedValue.types) {
-			printParseWarning(returnExpr.Pos(), fmt.Sprintf("return value of %s must have type %s, got %s", vr.name.ToString(false), inferredTypesString(vr.taggedTypes), inferredTypesString(returnedValue.types)))
+			printParseWarning(returnExpr.Pos(), "return-type", fmt.Sprintf("return value of %s must have type %s, got %s", vr.name.ToString(false), inferredTypesString(vr.taggedTypes), inferredTypesString(returnedValue.types)))
 		}
 	}
 	for i := range fnExpr.arities {
@@ -838,9 +849,9 @@ func parseBody(seq Seq, ctx *ParseContext) []Expr {
 		res = append(res, expr)
 		if LINTER_MODE {
 			if defExpr, ok := expr.(*DefExpr); ok && !defExpr.isCreatedByMacro {
-				printParseWarning(defExpr.Pos(), "inline def")
+				printParseWarning(defExpr.Pos(), "inline-def", "inline def")
 			} else if doExpr, ok := expr.(*DoExpr); ok && !doExpr.isCreatedByMacro && !skipRedundantDo(ro) {
-				printParseWarning(doExpr.Pos(), "redundant do form")
+				printParseWarning(doExpr.Pos(), "redundant-do", "redundant do form")
 			}
 		}
 	}
@@ -941,7 +952,7 @@ func addArity(fn *FnExpr, sig Seq, ctx *ParseContext) {
 	if LINTER_MODE {
 		if WARNINGS.fnWithEmptyBody {
 			if len(arity.body) == 0 {
-				printParseWarning(arity.Position, "fn form with empty body")
+				printParseWarning(arity.Position, "fn-with-empty-body", "fn form with empty body")
 			}
 		}
 
@@ -954,7 +965,7 @@ func addArity(fn *FnExpr, sig Seq, ctx *ParseContext) {
 			}
 			sort.Sort(BySymbolName(unused))
 			for _, u := range unused {
-				printParseWarning(GetPosition(u), "unused parameter: "+u.ToString(false))
+				printParseWarning(GetPosition(u), "unused-fn-parameters", "unused parameter: "+u.ToString(false))
 			}
 		}
 	}
@@ -1096,13 +1107,13 @@ func parseTry(obj Object, ctx *ParseContext) *TryExpr {
 	}
 	if LINTER_MODE {
 		if res.body == nil {
-			printParseWarning(res.Pos(), "try form with empty body")
+			printParseWarning(res.Pos(), "try-with-empty-body", "try form with empty body")
 		}
 		if res.catches == nil && res.finallyExpr == nil {
-			printParseWarning(res.Pos(), "try form without catch or finally")
+			printParseWarning(res.Pos(), "try-without-catch", "try form without catch or finally")
 		}
 		if res.finallyExpr != nil && len(res.finallyExpr) == 0 {
-			printParseWarning(GetPosition(obj), "finally form with empty body")
+			printParseWarning(GetPosition(obj), "finally-with-empty-body", "finally form with empty body")
 		}
 	}
 	return res
@@ -1142,7 +1153,7 @@ func parseLetLoop(obj Object, formName string, ctx *ParseContext) *LetExpr {
 		}
 		if LINTER_MODE && formName != "loop" && cnt == 0 {
 			pos := GetPosition(obj)
-			printParseWarning(pos, formName+" form with empty bindings vector")
+			printParseWarning(pos, "empty-bindings", formName+" form with empty bindings vector")
 		}
 		skipUnused := isSkipUnused(b)
 		res.names = make([]Symbol, cnt/2)
@@ -1158,7 +1169,7 @@ func parseLetLoop(obj Object, formName string, ctx *ParseContext) *LetExpr {
 				if sym.ns != nil {
 					msg := "Can't let qualified name: " + sym.ToString(false)
 					if LINTER_MODE {
-						printParseError(GetPosition(s), msg)
+						printParseError(GetPosition(s), "qualified-binding", msg)
 					} else {
 						panic(&ParseError{obj: s, msg: msg})
 					}
@@ -1199,7 +1210,7 @@ func parseLetLoop(obj Object, formName string, ctx *ParseContext) *LetExpr {
 		if LINTER_MODE {
 			if len(res.body) == 0 {
 				pos := GetPosition(obj)
-				printParseWarning(pos, formName+" form with empty body")
+				printParseWarning(pos, "empty-body", formName+" form with empty body")
 			}
 
 			if !skipUnused {
@@ -1211,7 +1222,7 @@ func parseLetLoop(obj Object, formName string, ctx *ParseContext) *LetExpr {
 				}
 				sort.Sort(BySymbolName(unused))
 				for _, u := range unused {
-					printParseWarning(GetPosition(u), "unused binding: "+u.ToString(false))
+					printParseWarning(GetPosition(u), "unused-binding", "unused binding: "+u.ToString(false))
 				}
 			}
 		}
@@ -1274,7 +1285,7 @@ func resolveMacro(obj Object, ctx *ParseContext) *Var {
 
 func fixInfo(obj Object, info *ObjectInfo) Object {
 	switch s := obj.(type) {
-	case Nil:
+	case Nil, *Record:
 		return obj
 	case Seq:
 		objs := make([]Object, 0, 8)
@@ -1338,7 +1349,7 @@ func macroexpand1(seq Seq, ctx *ParseContext) Object {
 }
 
 func reportNotAFunction(pos Position, name string) {
-	printParseWarning(pos, name+" is not a function")
+	printParseWarning(pos, "not-a-function", name+" is not a function")
 }
 
 func getTaggedTypes(obj Meta) []*Type {
@@ -1403,7 +1414,7 @@ func reportWrongArity(expr *FnExpr, isMacro bool, call *CallExpr, pos Position)
 	if v := selectArity(expr, passedArgsCount); v != nil {
 		return false
 	}
-	printParseWarning(pos, fmt.Sprintf("Wrong number of args (%d) passed to %s", len(call.args), call.Name()))
+	printParseWarning(pos, "wrong-arity", fmt.Sprintf("Wrong number of args (%d) passed to %s", len(call.args), call.Name()))
 	return true
 }
 
@@ -1545,11 +1556,11 @@ func checkCall(expr Expr, isMacro bool, call *CallExpr, pos Position) {
 		reportWrongArity(expr, isMacro, call, pos)
 	case *MapExpr:
 		if argsCount == 0 || argsCount > 2 {
-			printParseWarning(pos, fmt.Sprintf("Wrong number of args (%d) passed to a map", argsCount))
+			printParseWarning(pos, "wrong-arity", fmt.Sprintf("Wrong number of args (%d) passed to a map", argsCount))
 		}
 	case *SetExpr:
 		if argsCount == 0 || argsCount > 1 {
-			printParseWarning(pos, fmt.Sprintf("Wrong number of args (%d) passed to a set", argsCount))
+			printParseWarning(pos, "wrong-arity", fmt.Sprintf("Wrong number of args (%d) passed to a set", argsCount))
 		}
 	case *LiteralExpr:
 		if _, ok := expr.obj.(Callable); !ok && !expr.isSurrogate {
@@ -1559,7 +1570,7 @@ func checkCall(expr Expr, isMacro bool, call *CallExpr, pos Position) {
 		switch expr.obj.(type) {
 		case Keyword:
 			if argsCount == 0 || argsCount > 2 {
-				printParseWarning(pos, fmt.Sprintf("Wrong number of args (%d) passed to %s", argsCount, call.Name()))
+				printParseWarning(pos, "wrong-arity", fmt.Sprintf("Wrong number of args (%d) passed to %s", argsCount, call.Name()))
 			}
 		}
 	case *RecurExpr:
@@ -1597,7 +1608,7 @@ func checkCallableArglist(vr *Var, call *CallExpr, pos Position) {
 		return
 	}
 	if !checkArglist(arglistSeq, len(call.args)) {
-		printParseWarning(pos, fmt.Sprintf("Wrong number of args (%d) passed to %s", len(call.args), call.Name()))
+		printParseWarning(pos, "wrong-arity", fmt.Sprintf("Wrong number of args (%d) passed to %s", len(call.args), call.Name()))
 	}
 }
 
@@ -1634,6 +1645,11 @@ func checkLinterCall(call *CallExpr, ctx *ParseContext, pos Position) {
 func parseList(obj Object, ctx *ParseContext) Expr {
 	expanded := macroexpand1(obj.(Seq), ctx)
 	if expanded != obj {
+		if max := MaxCallDepth(); max > 0 && ctx.macroDepth >= max {
+			panic(RT.newMacroStackOverflowError(max, GetPosition(obj)))
+		}
+		ctx.macroDepth++
+		defer func() { ctx.macroDepth-- }()
 		return Parse(expanded, ctx)
 	}
 	seq := obj.(Seq)
@@ -1657,7 +1673,7 @@ func parseList(obj Object, ctx *ParseContext) Expr {
 		case STR._if:
 			checkForm(obj, 3, 4)
 			if LINTER_MODE && SeqCount(seq) < 4 && WARNINGS.ifWithoutElse {
-				printParseWarning(pos, "missing else branch")
+				printParseWarning(pos, "if-without-else", "missing else branch")
 			}
 			return &IfExpr{
 				cond:     Parse(Second(seq), ctx),
@@ -1697,7 +1713,7 @@ func parseList(obj Object, ctx *ParseContext) Expr {
 					symNs := ctx.GlobalEnv.NamespaceFor(ctx.GlobalEnv.CurrentNamespace(), sym)
 					if !ctx.isUnknownCallableScope {
 						if symNs == nil || symNs == ctx.GlobalEnv.CurrentNamespace() {
-							printParseError(GetPosition(obj), "Unable to resolve symbol: "+sym.ToString(false))
+							printParseError(GetPosition(obj), "unresolved-symbol", "Unable to resolve symbol: "+sym.ToString(false))
 						}
 					}
 					vr = InternFakeSymbol(symNs, sym)
@@ -1721,9 +1737,9 @@ func parseList(obj Object, ctx *ParseContext) Expr {
 			}
 			if LINTER_MODE {
 				if len(res.body) == 0 {
-					printParseWarning(pos, "do form with empty body")
+					printParseWarning(pos, "empty-body", "do form with empty body")
 				} else if len(res.body) == 1 {
-					printParseWarning(pos, "redundant do form")
+					printParseWarning(pos, "redundant-do", "redundant do form")
 				}
 			}
 			return res
@@ -1762,6 +1778,9 @@ func parseList(obj Object, ctx *ParseContext) Expr {
 		args:     parseSeq(seq.Rest(), ctx),
 		Position: pos,
 	}
+	if DEBUGGER != nil {
+		res.debug = &debugInfo{locals: ctx.localBindings}
+	}
 	if LINTER_MODE {
 		checkLinterCall(res, ctx, pos)
 	}
@@ -1864,7 +1883,7 @@ func parseSymbol(obj Object, ctx *ParseContext) Expr {
 		}
 		if !ctx.isUnknownCallableScope {
 			if ctx.linterBindings.GetBinding(sym) == nil {
-				printParseError(GetPosition(obj), "Unable to resolve symbol: "+sym.ToString(false))
+				printParseError(GetPosition(obj), "unresolved-symbol", "Unable to resolve symbol: "+sym.ToString(false))
 			}
 		}
 	}
@@ -1876,7 +1895,7 @@ func Parse(obj Object, ctx *ParseContext) Expr {
 	var res Expr
 	canHaveMeta := false
 	switch v := obj.(type) {
-	case Nil:
+	case Nil, *Record:
 		res = NewLiteralExpr(obj)
 	case Vec:
 		canHaveMeta = true
@@ -1916,6 +1935,10 @@ func TryParse(obj Object, ctx *ParseContext) (expr Expr, err error) {
 				err = r.(error)
 			case *EvalError:
 				err = r.(error)
+			case *StackOverflowError:
+				err = r.(error)
+			case *InterruptedError:
+				err = r.(error)
 			case *ExInfo:
 				err = r.(error)
 			default:
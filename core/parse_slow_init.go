@@ -27,7 +27,14 @@ var (
 		ifWithoutElse:      MakeKeyword("if-without-else"),
 		unusedFnParameters: MakeKeyword("unused-fn-parameters"),
 		fnWithEmptyBody:    MakeKeyword("fn-with-empty-body"),
+		linters:            MakeKeyword("linters"),
+		level:              MakeKeyword("level"),
+		off:                MakeKeyword("off"),
+		warning:            MakeKeyword("warning"),
+		error:              MakeKeyword("error"),
+		jokerIgnore:        MakeKeyword("joker/ignore"),
 		_prefix:            MakeKeyword("_prefix"),
+		_code:              MakeKeyword("_code"),
 		pos:                MakeKeyword("pos"),
 		startLine:          MakeKeyword("start-line"),
 		endLine:            MakeKeyword("end-line"),
@@ -54,6 +61,8 @@ var (
 		ascii:              MakeKeyword("ascii"),
 		unicode:            MakeKeyword("unicode"),
 		any:                MakeKeyword("any"),
+		default_:           MakeKeyword("default"),
+		methodTable:        MakeKeyword("method-table"),
 	}
 	SYMBOLS = Symbols{
 		joker_core:         MakeSymbol("joker.core"),
@@ -90,6 +99,8 @@ var (
 		hashMap:            MakeSymbol("hash-map"),
 		hashSet:            MakeSymbol("hash-set"),
 		defaultDataReaders: MakeSymbol("default-data-readers"),
+		printMethod:        MakeSymbol("print-method"),
+		printDup:           MakeSymbol("print-dup"),
 		backslash:          MakeSymbol("/"),
 		deref:              MakeSymbol("deref"),
 		ns:                 MakeSymbol("ns"),
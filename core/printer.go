@@ -0,0 +1,288 @@
+package core
+
+import (
+	"fmt"
+	"io"
+)
+
+// printer writes objects the way pr does. Built-in collections are printed
+// element by element so that *print-length*, *print-level*, *print-meta* and
+// *print-namespace-maps* apply at every level, and methods added to the
+// print-method (and, when *print-dup* is true, print-dup) multimethods
+// take precedence over the built-in representation.
+type printer struct {
+	w          io.Writer
+	writer     Object
+	readably   bool
+	length     int
+	level      int
+	meta       bool
+	nsMaps     bool
+	methods    Map
+	dupMethods Map
+}
+
+// printDepth is the collection nesting level of the object currently being
+// printed by a print-method method, so that objects it prints in turn
+// continue to honour *print-level*.
+var printDepth = 0
+
+func printLimit(v *Var) int {
+	if n, ok := v.Value.(Number); ok {
+		return n.Int().I
+	}
+	return -1
+}
+
+// printMethods returns the method table of the given multimethod in
+// joker.core, or nil if it has no methods other than :default.
+func printMethods(sym Symbol) Map {
+	v, ok := GLOBAL_ENV.CoreNamespace.mappings[sym.name]
+	if !ok {
+		return nil
+	}
+	mm, ok := v.Value.(Meta)
+	if !ok || mm.GetMeta() == nil {
+		return nil
+	}
+	ok, table := mm.GetMeta().Get(KEYWORDS.methodTable)
+	if !ok {
+		return nil
+	}
+	atom, ok := table.(*Atom)
+	if !ok {
+		return nil
+	}
+	methods, ok := atom.value.(Map)
+	if !ok || methods.Count() == 0 {
+		return nil
+	}
+	if ok, _ := methods.Get(KEYWORDS.default_); ok && methods.Count() == 1 {
+		return nil
+	}
+	return methods
+}
+
+func newPrinter(w io.Writer) *printer {
+	p := &printer{
+		w:        w,
+		readably: ToBool(GLOBAL_ENV.printReadably.Value),
+		length:   printLimit(GLOBAL_ENV.printLength),
+		level:    printLimit(GLOBAL_ENV.printLevel),
+		meta:     ToBool(GLOBAL_ENV.printMeta.Value),
+		nsMaps:   ToBool(GLOBAL_ENV.printNamespaceMaps.Value),
+		methods:  printMethods(SYMBOLS.printMethod),
+	}
+	if ToBool(GLOBAL_ENV.printDup.Value) {
+		p.dupMethods = printMethods(SYMBOLS.printDup)
+	}
+	return p
+}
+
+// printDispatchValue mirrors the dispatch function of print-method,
+// which is joker.core/type.
+func printDispatchValue(obj Object) Object {
+	if m, ok := obj.(Meta); ok && m.GetMeta() != nil {
+		if ok, t := m.GetMeta().Get(KEYWORDS.type_); ok && !t.Equals(NIL) {
+			return t
+		}
+	}
+	return obj.GetType()
+}
+
+func (p *printer) writerObject() Object {
+	if p.writer == nil {
+		if obj, ok := p.w.(Object); ok {
+			p.writer = obj
+		} else {
+			p.writer = MakeIOWriter(p.w)
+		}
+	}
+	return p.writer
+}
+
+func (p *printer) method(obj Object) Object {
+	if p.methods == nil && p.dupMethods == nil {
+		return nil
+	}
+	d := printDispatchValue(obj)
+	if p.dupMethods != nil {
+		if ok, m := p.dupMethods.Get(d); ok {
+			return m
+		}
+	}
+	if p.methods != nil {
+		if ok, m := p.methods.Get(d); ok {
+			return m
+		}
+	}
+	return nil
+}
+
+func (p *printer) print(obj Object, depth int) {
+	if m := p.method(obj); m != nil {
+		saved := printDepth
+		printDepth = depth
+		defer func() { printDepth = saved }()
+		EnsureObjectIsCallable(m, "print-method: %s").Call([]Object{obj, p.writerObject()})
+		return
+	}
+	p.printBuiltin(obj, depth)
+}
+
+func (p *printer) printBuiltin(obj Object, depth int) {
+	switch c := obj.(type) {
+	case Nil:
+		io.WriteString(p.w, "nil")
+	case *Record:
+		if p.printPrefix(c, depth) {
+			io.WriteString(p.w, "#"+c.rtype.name)
+			p.printMap(c, depth, false)
+		}
+	case *ArrayMap, *HashMap, *SortedMap:
+		if p.printPrefix(obj, depth) {
+			p.printMap(obj.(Map), depth, p.nsMaps)
+		}
+	case *Vector, *ArrayVector:
+		if p.printPrefix(obj, depth) {
+			p.printVector(obj.(CountedIndexed), depth)
+		}
+	case *MapSet, *SortedSet:
+		if p.printPrefix(obj, depth) {
+			p.printSeq("#{", "}", obj.(Seqable).Seq(), depth)
+		}
+	case Seq:
+		if p.printPrefix(obj, depth) {
+			p.printSeq("(", ")", c, depth)
+		}
+	case *TaggedLiteral:
+		io.WriteString(p.w, "#"+c.tag.ToString(false)+" ")
+		p.print(c.form, depth)
+	case Symbol:
+		p.printMeta(c, depth)
+		io.WriteString(p.w, c.ToString(p.readably))
+	case Printer:
+		c.Print(p.w, p.readably)
+	default:
+		fmt.Fprint(p.w, obj.ToString(p.readably))
+	}
+}
+
+// printPrefix prints the metadata of collection obj if *print-meta* is true.
+// It returns false, having printed "#" instead, if obj is nested deeper than
+// *print-level*.
+func (p *printer) printPrefix(obj Object, depth int) bool {
+	if p.level >= 0 && depth >= p.level {
+		io.WriteString(p.w, "#")
+		return false
+	}
+	p.printMeta(obj, depth)
+	return true
+}
+
+func (p *printer) printMeta(obj Object, depth int) {
+	if !p.meta || !p.readably {
+		return
+	}
+	m, ok := obj.(Meta)
+	if !ok || m.GetMeta() == nil || m.GetMeta().Count() == 0 {
+		return
+	}
+	meta := m.GetMeta()
+	io.WriteString(p.w, "^")
+	if ok, tag := meta.Get(KEYWORDS.tag); ok && meta.Count() == 1 {
+		p.print(tag, depth)
+	} else {
+		p.print(meta, depth)
+	}
+	io.WriteString(p.w, " ")
+}
+
+// more writes the separator before the n-th element and reports whether
+// the element should be printed; past *print-length* it writes "..." instead.
+func (p *printer) more(n int, sep string) bool {
+	if n > 0 {
+		io.WriteString(p.w, sep)
+	}
+	if p.length >= 0 && n >= p.length {
+		io.WriteString(p.w, "...")
+		return false
+	}
+	return true
+}
+
+func (p *printer) printSeq(open, close string, seq Seq, depth int) {
+	io.WriteString(p.w, open)
+	for n := 0; !seq.IsEmpty(); n, seq = n+1, seq.Rest() {
+		if !p.more(n, " ") {
+			break
+		}
+		p.print(seq.First(), depth+1)
+	}
+	io.WriteString(p.w, close)
+}
+
+func (p *printer) printVector(v CountedIndexed, depth int) {
+	io.WriteString(p.w, "[")
+	for i := 0; i < v.Count(); i++ {
+		if !p.more(i, " ") {
+			break
+		}
+		p.print(v.At(i), depth+1)
+	}
+	io.WriteString(p.w, "]")
+}
+
+func (p *printer) printMap(m Map, depth int, nsMaps bool) {
+	ns := ""
+	if nsMaps {
+		ns = mapNamespace(m)
+	}
+	if ns != "" {
+		io.WriteString(p.w, "#:"+ns)
+	}
+	io.WriteString(p.w, "{")
+	n := 0
+	for iter := m.Iter(); iter.HasNext(); n++ {
+		if !p.more(n, ", ") {
+			break
+		}
+		pair := iter.Next()
+		if ns != "" {
+			switch k := pair.Key.(type) {
+			case Keyword:
+				io.WriteString(p.w, ":"+*k.name)
+			case Symbol:
+				io.WriteString(p.w, *k.name)
+			}
+		} else {
+			p.print(pair.Key, depth+1)
+		}
+		io.WriteString(p.w, " ")
+		p.print(pair.Value, depth+1)
+	}
+	io.WriteString(p.w, "}")
+}
+
+// mapNamespace returns the namespace shared by all keys of m if they are all
+// namespaced keywords or symbols, and "" otherwise.
+func mapNamespace(m Map) string {
+	var ns *string
+	for iter := m.Iter(); iter.HasNext(); {
+		var keyNs *string
+		switch k := iter.Next().Key.(type) {
+		case Keyword:
+			keyNs = k.ns
+		case Symbol:
+			keyNs = k.ns
+		}
+		if keyNs == nil || (ns != nil && ns != keyNs) {
+			return ""
+		}
+		ns = keyNs
+	}
+	if ns == nil {
+		return ""
+	}
+	return *ns
+}
@@ -0,0 +1,200 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Problem is a warning or error found by the linter. Code is a stable
+// identifier of the kind of problem (e.g. "unused-binding") that can be
+// referenced in config.
+type Problem struct {
+	Position
+	Kind    string // "Parse warning", "Read error", etc.
+	Code    string
+	Message string
+}
+
+var (
+	// When COLLECT_PROBLEMS is set, problems are appended to PROBLEMS
+	// instead of being printed to Stderr.
+	COLLECT_PROBLEMS = false
+	PROBLEMS         []Problem
+)
+
+func (p Problem) Severity() string {
+	if strings.HasSuffix(p.Kind, "warning") {
+		return "warning"
+	}
+	return "error"
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s", p.Filename(), p.startLine, p.startColumn, p.Kind, p.Message)
+}
+
+// End returns the line and column of the last character of the
+// problem's form, or its start if the end is unknown.
+func (p Problem) End() (line, column int) {
+	if p.endLine == 0 {
+		return p.startLine, p.startColumn
+	}
+	return p.endLine, p.endColumn
+}
+
+func reportProblem(p Problem) {
+	if COLLECT_PROBLEMS {
+		PROBLEMS = append(PROBLEMS, p)
+	} else {
+		fmt.Fprintln(Stderr, p)
+	}
+}
+
+// ReportError reports an error that stopped processing a form (as
+// returned by TryRead, TryParse or TryEval).
+func ReportError(err error) {
+	if !COLLECT_PROBLEMS {
+		fmt.Fprintln(Stderr, err)
+		return
+	}
+	PROBLEMS = append(PROBLEMS, errorProblem(err))
+}
+
+func errorProblem(err error) Problem {
+	switch err := err.(type) {
+	case ReadError:
+		pos := Position{filename: err.filename, startLine: err.line, startColumn: err.column}
+		return Problem{Position: pos, Kind: "Read error", Code: "read-error", Message: err.msg}
+	case *ParseError:
+		var pos Position
+		if info := err.obj.GetInfo(); info != nil {
+			pos = info.Pos()
+		}
+		return Problem{Position: pos, Kind: "Parse error", Code: "parse-error", Message: err.msg}
+	case *StackOverflowError:
+		return errorProblem(&err.EvalError)
+	case *InterruptedError:
+		return errorProblem(&err.EvalError)
+	case *EvalError:
+		pos := err.pos
+		if len(err.rt.callstack.frames) > 0 {
+			pos = err.rt.callstack.frames[0].traceable.Pos()
+		}
+		return Problem{Position: pos, Kind: "Eval error", Code: "eval-error", Message: err.msg}
+	case *ExInfo:
+		var pos Position
+		_, data := err.Get(KEYWORDS.data)
+		if ok, form := data.(Map).Get(KEYWORDS.form); ok && form.GetInfo() != nil {
+			pos = form.GetInfo().Pos()
+		}
+		kind, code := "Exception", "exception"
+		if ok, pr := data.(Map).Get(KEYWORDS._prefix); ok {
+			kind = pr.ToString(false)
+		}
+		if ok, c := data.(Map).Get(KEYWORDS._code); ok {
+			code = c.ToString(false)
+		}
+		_, msg := err.Get(KEYWORDS.message)
+		return Problem{Position: pos, Kind: kind, Code: code, Message: msg.ToString(false)}
+	}
+	return Problem{Kind: "Error", Code: "error", Message: err.Error()}
+}
+
+// ignoredForm is a form preceded by #_:joker/ignore or a
+// ";; joker:ignore [code...]" comment. Problems within it are not
+// reported (only those with the given codes, if any).
+type ignoredForm struct {
+	Position
+	codes []string
+}
+
+// Ignored forms by file name.
+var ignoredForms = map[string][]ignoredForm{}
+
+// parseIgnoreComment returns the codes in an ignore comment
+// (";; joker:ignore code..."), with ok = false if comment isn't one.
+func parseIgnoreComment(comment string) (codes []string, ok bool) {
+	fields := strings.Fields(strings.TrimLeft(comment, ";"))
+	if len(fields) == 0 || fields[0] != "joker:ignore" {
+		return nil, false
+	}
+	return fields[1:], true
+}
+
+func (f ignoredForm) contains(p Problem) bool {
+	line, column := p.Start()
+	if p.Filename() != f.Filename() ||
+		line < f.startLine || (line == f.startLine && column < f.startColumn) ||
+		line > f.endLine || (line == f.endLine && column > f.endColumn) {
+		return false
+	}
+	if len(f.codes) == 0 {
+		return true
+	}
+	for _, code := range f.codes {
+		if code == p.Code {
+			return true
+		}
+	}
+	return false
+}
+
+func isIgnoredProblem(p Problem) bool {
+	for _, f := range ignoredForms[p.Filename()] {
+		if f.contains(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// reportLinterProblem reports a problem found by the linter at the
+// level configured for its code in :linters, unless it's turned off
+// there or ignored in the code.
+func reportLinterProblem(p Problem) {
+	switch level := WARNINGS.levels[p.Code]; level {
+	case "off":
+		return
+	case "warning", "error":
+		kind := strings.TrimSuffix(strings.TrimSuffix(p.Kind, " warning"), " error")
+		p.Kind = kind + " " + level
+	}
+	if isIgnoredProblem(p) {
+		return
+	}
+	PROBLEM_COUNT++
+	reportProblem(p)
+}
+
+// linterLevels returns levels by code from the value of :linters in
+// config, e.g. {:redundant-do {:level :off}}.
+func linterLevels(linters Object) (map[string]string, error) {
+	m, ok := linters.(Map)
+	if !ok {
+		return nil, errors.New(":linters value must be a map, got " + linters.GetType().ToString(false))
+	}
+	levels := make(map[string]string)
+	for iter := m.Iter(); iter.HasNext(); {
+		p := iter.Next()
+		code, ok := p.Key.(Keyword)
+		if !ok {
+			return nil, errors.New(":linters keys must be keywords, got " + p.Key.GetType().ToString(false))
+		}
+		opts, ok := p.Value.(Map)
+		if !ok {
+			return nil, errors.New(":linters values must be maps, got " + p.Value.GetType().ToString(false))
+		}
+		ok, level := opts.Get(KEYWORDS.level)
+		if !ok {
+			continue
+		}
+		switch {
+		case level.Equals(KEYWORDS.off), level.Equals(KEYWORDS.warning), level.Equals(KEYWORDS.error):
+			levels[code.Name()] = level.(Keyword).Name()
+		default:
+			return nil, errors.New(":level value (in :linters) must be :off, :warning or :error; got " + level.ToString(true))
+		}
+	}
+	return levels, nil
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"bufio"
 	"bytes"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -130,6 +131,10 @@ func ExtractBigFloat(args []Object, index int) *big.Float {
 	return EnsureArgIsBigFloat(args, index).b
 }
 
+func ExtractBytes(args []Object, index int) []byte {
+	return EnsureArgIsBytes(args, index).b
+}
+
 func ExtractRegex(args []Object, index int) *regexp.Regexp {
 	return EnsureArgIsRegex(args, index).R
 }
@@ -443,6 +448,133 @@ var procSubs = func(args []Object) Object {
 	return String{S: string([]rune(s)[start:end])}
 }
 
+var procBytes = func(args []Object) Object {
+	switch x := args[0].(type) {
+	case *Bytes:
+		return x
+	case String:
+		return MakeBytes([]byte(x.S))
+	case Seqable:
+		var res []byte
+		for s := x.Seq(); !s.IsEmpty(); s = s.Rest() {
+			n := EnsureObjectIsInt(s.First(), "Byte value: %s").I
+			if n < -128 || n > 255 {
+				panic(RT.NewError(fmt.Sprintf("Value out of range for byte: %d", n)))
+			}
+			res = append(res, byte(n))
+		}
+		return MakeBytes(res)
+	default:
+		panic(FailArg(x, "String, Bytes or Seqable", 0))
+	}
+}
+
+var procReadBytes = func(args []Object) Object {
+	s := EnsureArgIsString(args, 0).S
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(RT.NewError("Invalid bytes literal: " + err.Error()))
+	}
+	return MakeBytes(b)
+}
+
+// instLayouts are the forms of RFC 3339 timestamps accepted by #inst,
+// from least to most precise. Missing fields default to their minimum
+// and a missing offset to UTC.
+var instLayouts = []string{
+	"2006",
+	"2006-01",
+	"2006-01-02",
+	"2006-01-02T15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05.999999999",
+}
+
+var procReadInst = func(args []Object) Object {
+	s := EnsureArgIsString(args, 0).S
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return MakeTime(t)
+	}
+	for _, layout := range instLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return MakeTime(t)
+		}
+		if t, err := time.Parse(layout+"Z07:00", s); err == nil {
+			return MakeTime(t)
+		}
+	}
+	panic(RT.NewError("Invalid inst literal: " + s))
+}
+
+var procReadUUID = func(args []Object) Object {
+	s := EnsureArgIsString(args, 0).S
+	u, err := ParseUUID(s)
+	if err != nil {
+		panic(RT.NewError("Invalid uuid literal: " + s))
+	}
+	return u
+}
+
+var procParseUUID = func(args []Object) Object {
+	u, err := ParseUUID(EnsureArgIsString(args, 0).S)
+	if err != nil {
+		return NIL
+	}
+	return u
+}
+
+var procRandomUUID = func(args []Object) Object {
+	return RandomUUID()
+}
+
+var procInstMs = func(args []Object) Object {
+	return Int{I: int(EnsureArgIsTime(args, 0).T.UnixNano() / int64(time.Millisecond))}
+}
+
+var procTaggedLiteral = func(args []Object) Object {
+	return MakeTaggedLiteral(EnsureArgIsSymbol(args, 0), args[1])
+}
+
+var procBreak = func(args []Object) Object {
+	if DEBUGGER != nil {
+		DEBUGGER.breakHere()
+	}
+	return NIL
+}
+
+// procCallPosition returns the position of the call expression
+// that called the innermost function being evaluated.
+var procCallPosition = func(args []Object) Object {
+	frames := RT.callstack.frames
+	if len(frames) == 0 {
+		return NIL
+	}
+	pos := frames[len(frames)-1].traceable.Pos()
+	res := EmptyArrayMap()
+	res.Add(KEYWORDS.file, MakeString(pos.Filename()))
+	res.Add(KEYWORDS.line, Int{I: pos.startLine})
+	res.Add(KEYWORDS.column, Int{I: pos.startColumn})
+	return res
+}
+
+var procByteCount = func(args []Object) Object {
+	return Int{I: len(ToBytes(args[0], "byte-count: %s"))}
+}
+
+var procSubbytes = func(args []Object) Object {
+	b := EnsureArgIsBytes(args, 0)
+	start := EnsureArgIsInt(args, 1).I
+	end := b.Count()
+	if len(args) > 2 {
+		end = EnsureArgIsInt(args, 2).I
+	}
+	return b.Subbytes(start, end)
+}
+
+var procBytesToString = func(args []Object) Object {
+	return MakeString(string(EnsureArgIsBytes(args, 0).b))
+}
+
 var procIntern = func(args []Object) Object {
 	ns := EnsureArgIsNamespace(args, 0)
 	sym := EnsureArgIsSymbol(args, 1)
@@ -469,19 +601,42 @@ var procAtom = func(args []Object) Object {
 		if ok, v := m.Get(KEYWORDS.meta); ok {
 			res.meta = EnsureObjectIsMap(v, "")
 		}
+		if ok, v := m.Get(MakeKeyword("validator")); ok && !v.Equals(NIL) {
+			res.validator = EnsureObjectIsCallable(v, "validator: %s")
+			res.validate(res.value)
+		}
 	}
 	return res
 }
 
+var procClose = func(args []Object) Object {
+	c, ok := args[0].(Closer)
+	if !ok {
+		panic(RT.NewError("Object is not closable: " + args[0].ToString(false)))
+	}
+	PanicOnErr(c.Close())
+	return NIL
+}
+
 var procDeref = func(args []Object) Object {
-	return EnsureArgIsDeref(args, 0).Deref()
+	CheckArity(args, 1, 3)
+	if len(args) == 1 {
+		return EnsureArgIsDeref(args, 0).Deref()
+	}
+	CheckArity(args, 3, 3)
+	ref := EnsureArgIsBlockingDeref(args, 0)
+	ms := EnsureArgIsNumber(args, 1).Int().I
+	if res, ok := ref.DerefTimeout(time.Duration(ms) * time.Millisecond); ok {
+		return res
+	}
+	return args[2]
 }
 
 var procSwap = func(args []Object) Object {
 	a := EnsureArgIsAtom(args, 0)
 	f := EnsureArgIsCallable(args, 1)
 	fargs := append([]Object{a.value}, args[2:]...)
-	a.value = f.Call(fargs)
+	a.setValue(a, &a.value, f.Call(fargs))
 	return a.value
 }
 
@@ -490,23 +645,67 @@ var procSwapVals = func(args []Object) Object {
 	f := EnsureArgIsCallable(args, 1)
 	fargs := append([]Object{a.value}, args[2:]...)
 	oldValue := a.value
-	a.value = f.Call(fargs)
+	a.setValue(a, &a.value, f.Call(fargs))
 	return NewVectorFrom(oldValue, a.value)
 }
 
 var procReset = func(args []Object) Object {
 	a := EnsureArgIsAtom(args, 0)
-	a.value = args[1]
+	a.setValue(a, &a.value, args[1])
 	return a.value
 }
 
 var procResetVals = func(args []Object) Object {
 	a := EnsureArgIsAtom(args, 0)
 	oldValue := a.value
-	a.value = args[1]
+	a.setValue(a, &a.value, args[1])
 	return NewVectorFrom(oldValue, a.value)
 }
 
+var procAddWatch = func(args []Object) Object {
+	CheckArity(args, 3, 3)
+	r := EnsureArgIsWatchable(args, 0)
+	r.AddWatch(args[1], EnsureArgIsCallable(args, 2))
+	return args[0]
+}
+
+var procRemoveWatch = func(args []Object) Object {
+	CheckArity(args, 2, 2)
+	EnsureArgIsWatchable(args, 0).RemoveWatch(args[1])
+	return args[0]
+}
+
+var procSetValidator = func(args []Object) Object {
+	CheckArity(args, 2, 2)
+	r := EnsureArgIsWatchable(args, 0)
+	if args[1].Equals(NIL) {
+		r.SetValidator(nil)
+		return NIL
+	}
+	f := EnsureArgIsCallable(args, 1)
+	if v, ok := r.(*Var); !ok || v.Value != nil {
+		validateRefState(f, r.Deref())
+	}
+	r.SetValidator(f)
+	return NIL
+}
+
+var procGetValidator = func(args []Object) Object {
+	CheckArity(args, 1, 1)
+	if f := EnsureArgIsWatchable(args, 0).Validator(); f != nil {
+		return f.(Object)
+	}
+	return NIL
+}
+
+var procAlterVarRoot = func(args []Object) Object {
+	v := EnsureArgIsVar(args, 0)
+	f := EnsureArgIsCallable(args, 1)
+	fargs := append([]Object{v.Resolve()}, args[2:]...)
+	v.setValue(v, &v.Value, f.Call(fargs))
+	return v.Value
+}
+
 var procAlterMeta = func(args []Object) Object {
 	r := EnsureArgIsRef(args, 0)
 	f := EnsureArgIsFn(args, 1)
@@ -540,7 +739,8 @@ var procIsBound = func(args []Object) Object {
 // to a uint64 makes more sense than returning the stringized version,
 // for use cases such as `(format "%x" value)`. Even for BigFloat and
 // BigRat, try to (accurately) convert them to native types so they
-// can be formatted via the usual ways.
+// can be formatted via the usual ways. BigDecimal is wrapped so that
+// `(format "%s" value)` prints it exactly.
 func ToNative(obj Object) interface{} {
 	switch obj := obj.(type) {
 	case Native:
@@ -563,10 +763,43 @@ func ToNative(obj Object) interface{} {
 		if f, exact := b.Float64(); exact {
 			return f
 		}
+	case *BigDecimal:
+		return nativeBigDecimal{d: obj}
+	case *SortedMap:
+		var elems []interface{}
+		for iter := obj.Iter(); iter.HasNext(); {
+			p := iter.Next()
+			elems = append(elems, []interface{}{ToNative(p.Key), ToNative(p.Value)})
+		}
+		return nativeSorted{obj: obj, elems: elems}
+	case *SortedSet:
+		var elems []interface{}
+		for s := obj.Seq(); !s.IsEmpty(); s = s.Rest() {
+			elems = append(elems, ToNative(s.First()))
+		}
+		return nativeSorted{obj: obj, elems: elems}
 	}
 	return obj.ToString(false)
 }
 
+// nativeSorted is the native form of a sorted collection. It prints
+// as the collection itself for %s and %v, while other verbs
+// (such as %d or %x) are applied to its elements in order, with
+// map entries as two-element slices.
+type nativeSorted struct {
+	obj   Object
+	elems []interface{}
+}
+
+func (n nativeSorted) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 's', 'v':
+		io.WriteString(f, n.obj.ToString(false))
+	default:
+		fmt.Fprintf(f, fmt.FormatString(f, verb), n.elems)
+	}
+}
+
 var procFormat = func(args []Object) Object {
 	s := EnsureArgIsString(args, 0)
 	objs := args[1:]
@@ -707,7 +940,7 @@ func str(args ...Object) string {
 		if !obj.Equals(NIL) {
 			t := obj.GetType()
 			// TODO: this is a hack. Rethink escape parameter in ToString
-			escaped := (t == TYPE.String) || (t == TYPE.Char) || (t == TYPE.Regex)
+			escaped := (t == TYPE.String) || (t == TYPE.Char) || (t == TYPE.Regex) || (t == TYPE.Time) || (t == TYPE.UUID)
 			buffer.WriteString(obj.ToString(!escaped))
 		}
 	}
@@ -796,24 +1029,27 @@ var procIdentical = func(args []Object) Object {
 	return Boolean{B: args[0] == args[1]}
 }
 
-var procCompare = func(args []Object) Object {
-	k1, k2 := args[0], args[1]
+func compareObjects(k1, k2 Object) int {
 	if k1.Equals(k2) {
-		return Int{I: 0}
+		return 0
 	}
 	switch k2.(type) {
 	case Nil:
-		return Int{I: 1}
+		return 1
 	}
 	switch k1 := k1.(type) {
 	case Nil:
-		return Int{I: -1}
+		return -1
 	case Comparable:
-		return Int{I: k1.Compare(k2)}
+		return k1.Compare(k2)
 	}
 	panic(RT.NewError(fmt.Sprintf("%s (type: %s) is not a Comparable", k1.ToString(true), k1.GetType().ToString(false))))
 }
 
+var procCompare = func(args []Object) Object {
+	return Int{I: compareObjects(args[0], args[1])}
+}
+
 var procInt = func(args []Object) Object {
 	switch obj := args[0].(type) {
 	case Char:
@@ -883,9 +1119,8 @@ var procBigFloat = func(args []Object) Object {
 	case Number:
 		return &BigFloat{b: n.BigFloat()}
 	case String:
-		b := &big.Float{}
-		if _, ok := b.SetString(n.S); ok {
-			return &BigFloat{b: b}
+		if b, ok := MakeBigFloatWithOrig(n.S, ""); ok {
+			return b
 		}
 		panic(RT.NewError("Invalid number format " + n.S))
 	default:
@@ -893,6 +1128,35 @@ var procBigFloat = func(args []Object) Object {
 	}
 }
 
+var procBigDecimal = func(args []Object) Object {
+	switch n := args[0].(type) {
+	case Number:
+		return toBigDecimal(n)
+	case String:
+		if d, ok := MakeBigDecimalWithOrig(n.S, ""); ok {
+			return d
+		}
+		panic(RT.NewError("Invalid number format " + n.S))
+	default:
+		panic(RT.NewError(fmt.Sprintf("Cannot cast %s (type: %s) to BigDecimal", n.ToString(true), n.GetType().ToString(false))))
+	}
+}
+
+var procMathContext = func(args []Object) Object {
+	precision := EnsureArgIsInt(args, 0)
+	if precision.I < 0 {
+		panic(RT.NewError("Precision must be a non-negative Int, got " + precision.ToString(false)))
+	}
+	rounding := EnsureArgIsSymbol(args, 1)
+	if _, ok := ParseRoundingMode(rounding.ToString(false)); !ok {
+		panic(RT.NewError("Unknown rounding mode: " + rounding.ToString(false)))
+	}
+	res := EmptyArrayMap()
+	res.Add(MakeKeyword("precision"), precision)
+	res.Add(MakeKeyword("rounding"), rounding)
+	return res
+}
+
 var procNth = func(args []Object) Object {
 	n := EnsureArgIsNumber(args, 1).Int().I
 	switch coll := args[0].(type) {
@@ -1098,13 +1362,13 @@ var procPprint = func(args []Object) Object {
 }
 
 func PrintObject(obj Object, w io.Writer) {
-	printReadably := ToBool(GLOBAL_ENV.printReadably.Value)
-	switch obj := obj.(type) {
-	case Printer:
-		obj.Print(w, printReadably)
-	default:
-		fmt.Fprint(w, obj.ToString(printReadably))
-	}
+	newPrinter(w).print(obj, printDepth)
+}
+
+var procPrintMethod = func(args []Object) Object {
+	w := EnsureArgIsio_Writer(args, 1)
+	newPrinter(w).printBuiltin(args[0], printDepth)
+	return NIL
 }
 
 var procPr = func(args []Object) Object {
@@ -1354,7 +1618,9 @@ var procVarGet = func(args []Object) Object {
 }
 
 var procVarSet = func(args []Object) Object {
-	EnsureArgIsVar(args, 0).Value = args[1]
+	v := EnsureArgIsVar(args, 0)
+	v.validate(args[1])
+	v.Value = args[1]
 	return args[1]
 }
 
@@ -1490,7 +1756,11 @@ var procLoadFile = func(args []Object) Object {
 var procLoadLibFromPath = func(args []Object) Object {
 	libname := EnsureArgIsSymbol(args, 0).Name()
 	pathname := EnsureArgIsString(args, 1).S
+	checkSandboxLibPath(libname, pathname)
 	cp := GLOBAL_ENV.classPath.Value
+	if SANDBOX != nil {
+		cp = SANDBOX.classPath
+	}
 	cpvec := EnsureObjectIsVec(cp, "*classpath*: %s")
 	count := cpvec.Count()
 	var f *os.File
@@ -1532,12 +1802,37 @@ var procReduceKv = func(args []Object) Object {
 var procReduce = func(args []Object) Object {
 	f := EnsureArgIsCallable(args, 0)
 	if len(args) == 2 {
-		coll := EnsureArgIsReduce(args, 1)
-		return coll.reduce(f)
+		switch coll := args[1].(type) {
+		case Reduce:
+			return coll.reduce(f)
+		default:
+			return reduceSeqNoInit(f, EnsureArgIsSeqable(args, 1).Seq())
+		}
 	}
 	init := args[1]
-	coll := EnsureArgIsReduce(args, 2)
-	return coll.reduceInit(f, init)
+	switch coll := args[2].(type) {
+	case Reduce:
+		return coll.reduceInit(f, init)
+	default:
+		return reduceSeq(f, init, EnsureArgIsSeqable(args, 2).Seq())
+	}
+}
+
+var procReduced = func(args []Object) Object {
+	CheckArity(args, 1, 1)
+	return &Reduced{val: args[0]}
+}
+
+var procVolatile = func(args []Object) Object {
+	CheckArity(args, 1, 1)
+	return &Volatile{value: args[0]}
+}
+
+var procVreset = func(args []Object) Object {
+	CheckArity(args, 2, 2)
+	v := EnsureArgIsVolatile(args, 0)
+	v.value = args[1]
+	return v.value
 }
 
 var procIndexOf = func(args []Object) Object {
@@ -1566,6 +1861,9 @@ func libExternalPath(sym Symbol) (path string, ok bool) {
 		}
 	}
 	if sourceMap != nil {
+		if SANDBOX != nil {
+			panic(RT.NewError("Loading " + sym.Name() + " from *ns-sources* is not allowed in sandbox mode"))
+		}
 		ok, url := sourceMap.Get(MakeKeyword("url"))
 		if !ok {
 			panic(RT.NewError("Key :url not found in ns-sources for: " + sourceKey))
@@ -1640,40 +1938,471 @@ var procTypes = func(args []Object) Object {
 	return res
 }
 
+// procFindProtocolImpl looks up the implementation map for type t
+// in a protocol's impls map. Exact type matches win, then other
+// concrete types (Record matches every record type), then interface
+// types other than Object, then Object. Of several matching interfaces
+// the most specific one wins (Map over Counted, as Map includes
+// Counted); of unrelated ones, the one whose name sorts first.
+// nil only matches Nil.
+var procFindProtocolImpl = func(args []Object) Object {
+	CheckArity(args, 2, 2)
+	impls := EnsureArgIsMap(args, 0)
+	t := EnsureArgIsType(args, 1)
+	if ok, impl := impls.Get(t); ok {
+		return impl
+	}
+	if t == TYPE.Nil {
+		return NIL
+	}
+	var best *Type
+	var res Object = NIL
+	for iter := impls.Iter(); iter.HasNext(); {
+		p := iter.Next()
+		it, ok := p.Key.(*Type)
+		if !ok || !IsEqualOrImplements(it, t) || it == TYPE.Object {
+			continue
+		}
+		if best == nil || preferImplType(it, best) {
+			best, res = it, p.Value
+		}
+	}
+	if best == nil {
+		if ok, impl := impls.Get(TYPE.Object); ok {
+			return impl
+		}
+	}
+	return res
+}
+
+// preferImplType reports whether protocol implementations for
+// type a take precedence over those for b (both matching the same type).
+func preferImplType(a, b *Type) bool {
+	aIsInterface := a.reflectType.Kind() == reflect.Interface
+	bIsInterface := b.reflectType.Kind() == reflect.Interface
+	if aIsInterface != bIsInterface {
+		return bIsInterface
+	}
+	if aIsInterface {
+		aExtendsB := a.reflectType.Implements(b.reflectType)
+		bExtendsA := b.reflectType.Implements(a.reflectType)
+		if aExtendsB != bExtendsA {
+			return aExtendsB
+		}
+	}
+	return a.name < b.name
+}
+
+var procCreateType = func(args []Object) Object {
+	CheckArity(args, 3, 3)
+	name := EnsureArgIsString(args, 0).S
+	fieldsVec := EnsureArgIsVec(args, 1)
+	fields := make([]Keyword, fieldsVec.Count())
+	for i := range fields {
+		fields[i] = EnsureObjectIsKeyword(fieldsVec.At(i), "")
+	}
+	return NewUserType(name, fields, ToBool(args[2]))
+}
+
+var procNewInstance = func(args []Object) Object {
+	CheckArity(args, 2, 2)
+	t := EnsureArgIsType(args, 0)
+	v := EnsureArgIsVec(args, 1)
+	values := make([]Object, v.Count())
+	for i := range values {
+		values[i] = v.At(i)
+	}
+	return NewInstance(t, values)
+}
+
+var procRecordFromMap = func(args []Object) Object {
+	CheckArity(args, 2, 2)
+	return NewRecordFromMap(EnsureArgIsType(args, 0), EnsureArgIsMap(args, 1))
+}
+
+var procField = func(args []Object) Object {
+	CheckArity(args, 2, 2)
+	return getField(args[0], EnsureArgIsKeyword(args, 1))
+}
+
+var procCreateReifyType = func(args []Object) Object {
+	CheckArity(args, 2, 2)
+	name := EnsureArgIsString(args, 0).S
+	ifaces := EnsureArgIsVec(args, 1)
+	interfaces := make([]*Type, ifaces.Count())
+	for i := range interfaces {
+		interfaces[i] = EnsureObjectIsType(ifaces.At(i), "")
+	}
+	return NewReifyType(name, interfaces)
+}
+
+var procReify = func(args []Object) Object {
+	CheckArity(args, 2, 2)
+	return NewReified(EnsureArgIsType(args, 0), EnsureArgIsMap(args, 1))
+}
+
+var procReifiedMethod = func(args []Object) Object {
+	CheckArity(args, 2, 2)
+	if r, ok := args[0].(*Reified); ok {
+		return r.method(EnsureArgIsSymbol(args, 1))
+	}
+	return NIL
+}
+
+var procTransient = func(args []Object) Object {
+	CheckArity(args, 1, 1)
+	return NewTransient(args[0])
+}
+
+var procIsEditable = func(args []Object) Object {
+	CheckArity(args, 1, 1)
+	return Boolean{B: IsEditable(args[0])}
+}
+
+var procPersistent = func(args []Object) Object {
+	CheckArity(args, 1, 1)
+	return EnsureArgIsTransient(args, 0).Persistent()
+}
+
+var procConjBang = func(args []Object) Object {
+	CheckArity(args, 2, 2)
+	return EnsureArgIsTransient(args, 0).Conj(args[1])
+}
+
+var procAssocBang = func(args []Object) Object {
+	CheckArity(args, 3, 3)
+	switch t := args[0].(type) {
+	case TransientAssociative:
+		return t.Assoc(args[1], args[2])
+	}
+	panic(RT.NewError("assoc! not supported on type " + args[0].GetType().ToString(false)))
+}
+
+var procDissocBang = func(args []Object) Object {
+	CheckArity(args, 2, 2)
+	switch t := args[0].(type) {
+	case TransientMap:
+		return t.Without(args[1])
+	}
+	panic(RT.NewError("dissoc! not supported on type " + args[0].GetType().ToString(false)))
+}
+
+var procDisjBang = func(args []Object) Object {
+	CheckArity(args, 2, 2)
+	switch t := args[0].(type) {
+	case *TransientSet:
+		return t.Disjoin(args[1])
+	}
+	panic(RT.NewError("disj! not supported on type " + args[0].GetType().ToString(false)))
+}
+
+var procPopBang = func(args []Object) Object {
+	CheckArity(args, 1, 1)
+	switch t := args[0].(type) {
+	case *TransientVector:
+		return t.Pop()
+	}
+	panic(RT.NewError("pop! not supported on type " + args[0].GetType().ToString(false)))
+}
+
+var procSortedMap = func(args []Object) Object {
+	return sortedMapFrom(nil, args)
+}
+
+var procSortedMapBy = func(args []Object) Object {
+	return sortedMapFrom(EnsureArgIsComparator(args, 0), args[1:])
+}
+
+func sortedMapFrom(comp Comparator, keyvals []Object) *SortedMap {
+	if len(keyvals)%2 != 0 {
+		panic(RT.NewError("No value supplied for key " + keyvals[len(keyvals)-1].ToString(false)))
+	}
+	var res Associative = NewSortedMap(comp)
+	for i := 0; i < len(keyvals); i += 2 {
+		res = res.Assoc(keyvals[i], keyvals[i+1])
+	}
+	return res.(*SortedMap)
+}
+
+var procSortedSet = func(args []Object) Object {
+	return sortedSetFrom(nil, args)
+}
+
+var procSortedSetBy = func(args []Object) Object {
+	return sortedSetFrom(EnsureArgIsComparator(args, 0), args[1:])
+}
+
+func sortedSetFrom(comp Comparator, keys []Object) *SortedSet {
+	var res Conjable = NewSortedSet(comp)
+	for _, k := range keys {
+		res = res.Conj(k)
+	}
+	return res.(*SortedSet)
+}
+
+func seqOrNil(s Seq) Object {
+	if s.IsEmpty() {
+		return NIL
+	}
+	return s
+}
+
+var procSortedSeq = func(args []Object) Object {
+	CheckArity(args, 2, 2)
+	return seqOrNil(EnsureArgIsSorted(args, 0).SortedSeq(EnsureArgIsBoolean(args, 1).B))
+}
+
+var procSortedSeqFrom = func(args []Object) Object {
+	CheckArity(args, 3, 3)
+	return seqOrNil(EnsureArgIsSorted(args, 0).SeqFrom(args[1], EnsureArgIsBoolean(args, 2).B))
+}
+
+// procSortedCompare compares the key of entry (an element of
+// the sorted collection's seq) with key using the collection's ordering.
+var procSortedCompare = func(args []Object) Object {
+	CheckArity(args, 3, 3)
+	sc := EnsureArgIsSorted(args, 0)
+	return Int{I: sc.CompareKeys(sc.EntryKey(args[1]), args[2])}
+}
+
+var procFutureCall = func(args []Object) Object {
+	CheckArity(args, 1, 1)
+	return MakeFuture(EnsureArgIsCallable(args, 0))
+}
+
+var procFutureCancel = func(args []Object) Object {
+	CheckArity(args, 1, 1)
+	return Boolean{B: EnsureArgIsFuture(args, 0).Cancel()}
+}
+
+var procIsFutureCancelled = func(args []Object) Object {
+	CheckArity(args, 1, 1)
+	return Boolean{B: EnsureArgIsFuture(args, 0).IsCancelled()}
+}
+
+var procPromise = func(args []Object) Object {
+	CheckArity(args, 0, 0)
+	return MakePromise()
+}
+
+var procDeliver = func(args []Object) Object {
+	CheckArity(args, 2, 2)
+	p := EnsureArgIsPromise(args, 0)
+	if p.Deliver(args[1]) {
+		return p
+	}
+	return NIL
+}
+
+var procAgent = func(args []Object) Object {
+	res := NewAgent(args[0])
+	if len(args) > 1 {
+		m := NewHashMap(args[1:]...)
+		if ok, v := m.Get(KEYWORDS.meta); ok {
+			res.meta = EnsureObjectIsMap(v, "")
+		}
+		if ok, v := m.Get(MakeKeyword("error-handler")); ok && !v.Equals(NIL) {
+			res.errorHandler = EnsureObjectIsCallable(v, "error-handler: %s")
+			res.failOnError = false
+		}
+		if ok, v := m.Get(MakeKeyword("error-mode")); ok {
+			res.failOnError = agentFailOnError(v)
+		}
+		if ok, v := m.Get(MakeKeyword("validator")); ok && !v.Equals(NIL) {
+			res.validator = EnsureObjectIsCallable(v, "validator: %s")
+			res.validate(res.value)
+		}
+	}
+	return res
+}
+
+func agentFailOnError(mode Object) bool {
+	switch EnsureObjectIsKeyword(mode, "error-mode: %s").Name() {
+	case "fail":
+		return true
+	case "continue":
+		return false
+	}
+	panic(RT.NewError("error-mode must be :fail or :continue, got " + mode.ToString(true)))
+}
+
+var procSendAgent = func(args []Object) Object {
+	a := EnsureArgIsAgent(args, 0)
+	a.Send(EnsureArgIsCallable(args, 1), args[2:])
+	return a
+}
+
+func agentsFromArgs(args []Object) []*Agent {
+	agents := make([]*Agent, len(args))
+	for i := range args {
+		agents[i] = EnsureArgIsAgent(args, i)
+	}
+	return agents
+}
+
+var procAwait = func(args []Object) Object {
+	AwaitAgents(agentsFromArgs(args), -1)
+	return NIL
+}
+
+var procAwaitFor = func(args []Object) Object {
+	ms := EnsureArgIsNumber(args, 0).Int().I
+	return Boolean{B: AwaitAgents(agentsFromArgs(args[1:]), time.Duration(ms)*time.Millisecond)}
+}
+
+var procAgentError = func(args []Object) Object {
+	CheckArity(args, 1, 1)
+	if err := EnsureArgIsAgent(args, 0).Error(); err != nil {
+		return err
+	}
+	return NIL
+}
+
+var procRestartAgent = func(args []Object) Object {
+	CheckArity(args, 3, 3)
+	a := EnsureArgIsAgent(args, 0)
+	a.Restart(args[1], ToBool(args[2]))
+	return args[1]
+}
+
+var procSetErrorHandler = func(args []Object) Object {
+	CheckArity(args, 2, 2)
+	a := EnsureArgIsAgent(args, 0)
+	if args[1].Equals(NIL) {
+		a.SetErrorHandler(nil)
+	} else {
+		a.SetErrorHandler(EnsureArgIsCallable(args, 1))
+	}
+	return NIL
+}
+
+var procErrorHandler = func(args []Object) Object {
+	CheckArity(args, 1, 1)
+	if h := EnsureArgIsAgent(args, 0).ErrorHandler(); h != nil {
+		return h.(Object)
+	}
+	return NIL
+}
+
+var procSetErrorMode = func(args []Object) Object {
+	CheckArity(args, 2, 2)
+	EnsureArgIsAgent(args, 0).SetFailOnError(agentFailOnError(args[1]))
+	return NIL
+}
+
+var procErrorMode = func(args []Object) Object {
+	CheckArity(args, 1, 1)
+	if EnsureArgIsAgent(args, 0).FailOnError() {
+		return MakeKeyword("fail")
+	}
+	return MakeKeyword("continue")
+}
+
+var procShutdownAgents = func(args []Object) Object {
+	CheckArity(args, 0, 0)
+	ShutdownAgents()
+	return NIL
+}
+
 var procCreateChan = func(args []Object) Object {
 	CheckArity(args, 1, 1)
+	switch buf := args[0].(type) {
+	case Nil:
+		return MakeChannel(make(chan FutureResult))
+	case *ChannelBuffer:
+		return MakeBufferedChannel(buf)
+	}
 	n := EnsureArgIsInt(args, 0)
+	if n.I < 0 {
+		panic(RT.NewArgTypeError(0, n, "non-negative Int"))
+	}
 	ch := make(chan FutureResult, n.I)
 	return MakeChannel(ch)
 }
 
+var procChannelBuffer = func(args []Object) Object {
+	CheckArity(args, 2, 2)
+	var kind ChannelBufferKind
+	switch k := EnsureArgIsKeyword(args, 0).Name(); k {
+	case "fixed":
+		kind = FixedBuffer
+	case "sliding":
+		kind = SlidingBuffer
+	case "dropping":
+		kind = DroppingBuffer
+	default:
+		panic(RT.NewError("Unknown channel buffer kind: " + k))
+	}
+	n := EnsureArgIsInt(args, 1)
+	if n.I < 0 || (n.I == 0 && kind != FixedBuffer) {
+		panic(RT.NewError("Buffer size must be positive, got " + n.ToString(false)))
+	}
+	return &ChannelBuffer{kind: kind, size: n.I}
+}
+
+var procTimeout = func(args []Object) Object {
+	CheckArity(args, 1, 1)
+	ms := EnsureArgIsNumber(args, 0).Int().I
+	ch := MakeChannel(make(chan FutureResult))
+	time.AfterFunc(time.Duration(ms)*time.Millisecond, func() {
+		RT.GIL.Lock()
+		defer RT.GIL.Unlock()
+		ch.Close()
+	})
+	return ch
+}
+
+var procAlts = func(args []Object) Object {
+	CheckArity(args, 2, 2)
+	var ops []altsOp
+	for s := EnsureArgIsSeqable(args, 0).Seq(); !s.IsEmpty(); s = s.Rest() {
+		switch port := s.First().(type) {
+		case *Channel:
+			ops = append(ops, altsOp{ch: port})
+		case Vec:
+			if port.Count() != 2 {
+				panic(RT.NewError("alts! put must be a vector of [channel val], got " + port.ToString(true)))
+			}
+			ch := EnsureObjectIsChannel(port.At(0), "alts! put: %s")
+			v := port.At(1)
+			if v.Equals(NIL) {
+				panic(RT.NewError("Can't put nil on channel"))
+			}
+			ops = append(ops, altsOp{ch: ch, val: v})
+		default:
+			panic(RT.NewError("alts! port must be a channel or a vector of [channel val], got " + port.GetType().ToString(false)))
+		}
+	}
+	if len(ops) == 0 {
+		panic(RT.NewError("alts! must have at least one port"))
+	}
+	var dflt Object
+	priority := false
+	if opts, ok := args[1].(Map); ok {
+		if ok, v := opts.Get(MakeKeyword("default")); ok {
+			dflt = v
+		}
+		if ok, v := opts.Get(MakeKeyword("priority")); ok {
+			priority = ToBool(v)
+		}
+	}
+	return Alts(ops, priority, dflt)
+}
+
 var procCloseChan = func(args []Object) Object {
 	CheckArity(args, 1, 1)
 	EnsureArgIsChannel(args, 0).Close()
 	return NIL
 }
 
-var procSend = func(args []Object) (obj Object) {
+var procSend = func(args []Object) Object {
 	CheckArity(args, 2, 2)
 	ch := EnsureArgIsChannel(args, 0)
 	v := args[1]
 	if v.Equals(NIL) {
 		panic(RT.NewError("Can't put nil on channel"))
 	}
-	if ch.isClosed {
-		return MakeBoolean(false)
-	}
-	obj = MakeBoolean(true)
-	defer func() {
-		if r := recover(); r != nil {
-			RT.GIL.Lock()
-			obj = MakeBoolean(false)
-		}
-	}()
-	RT.GIL.Unlock()
-	ch.ch <- MakeFutureResult(v, nil)
-	RT.GIL.Lock()
-	return
+	return MakeBoolean(ch.Put(v))
 }
 
 var procReceive = func(args []Object) Object {
@@ -1695,6 +2424,7 @@ var procGo = func(args []Object) Object {
 	CheckArity(args, 1, 1)
 	f := EnsureArgIsCallable(args, 0)
 	ch := MakeChannel(make(chan FutureResult, 1))
+	exitSession := EXIT_SESSION
 	go func() {
 
 		defer func() {
@@ -1703,6 +2433,9 @@ var procGo = func(args []Object) Object {
 				case Error:
 					ch.ch <- MakeFutureResult(NIL, r)
 					ch.Close()
+				case SessionExit:
+					ch.ch <- MakeFutureResult(NIL, endSession(exitSession))
+					ch.Close()
 				default:
 					RT.GIL.Unlock()
 					panic(r)
@@ -1712,6 +2445,7 @@ var procGo = func(args []Object) Object {
 		}()
 
 		RT.GIL.Lock()
+		EXIT_SESSION = exitSession
 		res := f.Call([]Object{})
 		ch.ch <- MakeFutureResult(res, nil)
 		ch.Close()
@@ -1748,6 +2482,8 @@ var procAbs = func(args []Object) Object {
 	case *Ratio:
 		r := &big.Rat{}
 		return &Ratio{r: r.Abs(n.r)}
+	case *BigDecimal:
+		return &BigDecimal{unscaled: new(big.Int).Abs(n.unscaled), scale: n.scale}
 	case Int:
 		x := n.I
 		if x < 0 {
@@ -1822,8 +2558,8 @@ func PackReader(reader *Reader, filename string) ([]byte, error) {
 	}
 }
 
-var procIncProblemCount = func(args []Object) Object {
-	PROBLEM_COUNT++
+var procReportProblem = func(args []Object) Object {
+	reportLinterProblem(errorProblem(EnsureArgIsError(args, 0)))
 	return NIL
 }
 
@@ -1832,6 +2568,10 @@ func ProcessReader(reader *Reader, filename string, phase Phase) error {
 		FORMAT_MODE = true
 		HASHMAP_THRESHOLD = 100000
 	}
+	if LINTER_MODE {
+		// Forget ignored forms from linting a previous version of the file.
+		delete(ignoredForms, *reader.filename)
+	}
 	parseContext := &ParseContext{GlobalEnv: GLOBAL_ENV}
 	if filename != "" {
 		currentFilename := parseContext.GlobalEnv.file.Value
@@ -1852,7 +2592,7 @@ func ProcessReader(reader *Reader, filename string, phase Phase) error {
 			return nil
 		}
 		if err != nil {
-			fmt.Fprintln(Stderr, err)
+			ReportError(err)
 			return err
 		}
 		if phase == READ {
@@ -1874,7 +2614,7 @@ func ProcessReader(reader *Reader, filename string, phase Phase) error {
 		}
 		expr, err := TryParse(obj, parseContext)
 		if err != nil {
-			fmt.Fprintln(Stderr, err)
+			ReportError(err)
 		}
 		if phase == PARSE {
 			continue
@@ -1884,7 +2624,7 @@ func ProcessReader(reader *Reader, filename string, phase Phase) error {
 		}
 		obj, err = TryEval(expr)
 		if err != nil {
-			fmt.Fprintln(Stderr, err)
+			ReportError(err)
 			return err
 		}
 		if phase == EVAL {
@@ -2135,6 +2875,24 @@ func ReadConfig(filename string, workingDir string) {
 			WARNINGS.fnWithEmptyBody = ToBool(v)
 		}
 	}
+	if ok, linters := configMap.Get(KEYWORDS.linters); ok {
+		levels, err := linterLevels(linters)
+		if err != nil {
+			printConfigError(configFileName, err.Error())
+			return
+		}
+		WARNINGS.levels = levels
+		// Optional rules are enabled by setting their level.
+		if level, ok := levels["if-without-else"]; ok {
+			WARNINGS.ifWithoutElse = level != "off"
+		}
+		if level, ok := levels["unused-fn-parameters"]; ok {
+			WARNINGS.unusedFnParameters = level != "off"
+		}
+		if level, ok := levels["fn-with-empty-body"]; ok {
+			WARNINGS.fnWithEmptyBody = level != "off"
+		}
+	}
 	if ok, valid := configMap.Get(KEYWORDS.validIdent); ok {
 		m, ok := valid.(Map)
 		if !ok {
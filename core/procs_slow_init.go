@@ -105,7 +105,21 @@ func init() {
 	intern("denominator__", procDenominator, "procDenominator")
 	intern("bigint__", procBigInt, "procBigInt")
 	intern("bigfloat__", procBigFloat, "procBigFloat")
+	intern("bigdec__", procBigDecimal, "procBigDecimal")
+	intern("bytes__", procBytes, "procBytes")
+	intern("read-bytes__", procReadBytes, "procReadBytes")
+	intern("read-inst__", procReadInst, "procReadInst")
+	intern("read-uuid__", procReadUUID, "procReadUUID")
+	intern("inst-ms__", procInstMs, "procInstMs")
+	intern("parse-uuid__", procParseUUID, "procParseUUID")
+	intern("random-uuid__", procRandomUUID, "procRandomUUID")
+	intern("tagged-literal__", procTaggedLiteral, "procTaggedLiteral")
+	intern("byte-count__", procByteCount, "procByteCount")
+	intern("subbytes__", procSubbytes, "procSubbytes")
+	intern("bytes->string__", procBytesToString, "procBytesToString")
+	intern("math-context__", procMathContext, "procMathContext")
 	intern("pr__", procPr, "procPr")
+	intern("print-method__", procPrintMethod, "procPrintMethod")
 	intern("pprint__", procPprint, "procPprint")
 	intern("newline__", procNewline, "procNewline")
 	intern("flush__", procFlush, "procFlush")
@@ -150,11 +164,17 @@ func init() {
 	intern("intern__", procIntern, "procIntern")
 	intern("set-meta__", procSetMeta, "procSetMeta")
 	intern("atom__", procAtom, "procAtom")
+	intern("close__", procClose, "procClose")
 	intern("deref__", procDeref, "procDeref")
 	intern("swap__", procSwap, "procSwap")
 	intern("swap-vals__", procSwapVals, "procSwapVals")
 	intern("reset__", procReset, "procReset")
 	intern("reset-vals__", procResetVals, "procResetVals")
+	intern("add-watch__", procAddWatch, "procAddWatch")
+	intern("remove-watch__", procRemoveWatch, "procRemoveWatch")
+	intern("set-validator!__", procSetValidator, "procSetValidator")
+	intern("get-validator__", procGetValidator, "procGetValidator")
+	intern("alter-var-root__", procAlterVarRoot, "procAlterVarRoot")
 	intern("alter-meta__", procAlterMeta, "procAlterMeta")
 	intern("reset-meta__", procResetMeta, "procResetMeta")
 	intern("empty__", procEmpty, "procEmpty")
@@ -164,6 +184,9 @@ func init() {
 	intern("load-lib-from-path__", procLoadLibFromPath, "procLoadLibFromPath")
 	intern("reduce-kv__", procReduceKv, "procReduceKv")
 	intern("reduce__", procReduce, "procReduce")
+	intern("reduced__", procReduced, "procReduced")
+	intern("volatile!__", procVolatile, "procVolatile")
+	intern("vreset!__", procVreset, "procVreset")
 	intern("slurp__", procSlurp, "procSlurp")
 	intern("spit__", procSpit, "procSpit")
 	intern("shuffle__", procShuffle, "procShuffle")
@@ -177,13 +200,55 @@ func init() {
 	intern("lib-path__", procLibPath, "procLibPath")
 	intern("intern-fake-var__", procInternFakeVar, "procInternFakeVar")
 	intern("parse__", procParse, "procParse")
-	intern("inc-problem-count__", procIncProblemCount, "procIncProblemCount")
+	intern("report-problem__", procReportProblem, "procReportProblem")
 	intern("types__", procTypes, "procTypes")
+	intern("find-protocol-impl__", procFindProtocolImpl, "procFindProtocolImpl")
+	intern("create-type__", procCreateType, "procCreateType")
+	intern("new-instance__", procNewInstance, "procNewInstance")
+	intern("record-from-map__", procRecordFromMap, "procRecordFromMap")
+	intern("field__", procField, "procField")
+	intern("create-reify-type__", procCreateReifyType, "procCreateReifyType")
+	intern("reify__", procReify, "procReify")
+	intern("reified-method__", procReifiedMethod, "procReifiedMethod")
+	intern("transient__", procTransient, "procTransient")
+	intern("editable?__", procIsEditable, "procIsEditable")
+	intern("persistent!__", procPersistent, "procPersistent")
+	intern("conj!__", procConjBang, "procConjBang")
+	intern("assoc!__", procAssocBang, "procAssocBang")
+	intern("dissoc!__", procDissocBang, "procDissocBang")
+	intern("disj!__", procDisjBang, "procDisjBang")
+	intern("pop!__", procPopBang, "procPopBang")
+	intern("sorted-map__", procSortedMap, "procSortedMap")
+	intern("sorted-map-by__", procSortedMapBy, "procSortedMapBy")
+	intern("sorted-set__", procSortedSet, "procSortedSet")
+	intern("sorted-set-by__", procSortedSetBy, "procSortedSetBy")
+	intern("sorted-seq__", procSortedSeq, "procSortedSeq")
+	intern("sorted-seq-from__", procSortedSeqFrom, "procSortedSeqFrom")
+	intern("sorted-compare__", procSortedCompare, "procSortedCompare")
 	intern("go__", procGo, "procGo")
+	intern("future-call__", procFutureCall, "procFutureCall")
+	intern("future-cancel__", procFutureCancel, "procFutureCancel")
+	intern("future-cancelled?__", procIsFutureCancelled, "procIsFutureCancelled")
+	intern("promise__", procPromise, "procPromise")
+	intern("deliver__", procDeliver, "procDeliver")
+	intern("agent__", procAgent, "procAgent")
+	intern("send__", procSendAgent, "procSendAgent")
+	intern("await__", procAwait, "procAwait")
+	intern("await-for__", procAwaitFor, "procAwaitFor")
+	intern("agent-error__", procAgentError, "procAgentError")
+	intern("restart-agent__", procRestartAgent, "procRestartAgent")
+	intern("set-error-handler!__", procSetErrorHandler, "procSetErrorHandler")
+	intern("error-handler__", procErrorHandler, "procErrorHandler")
+	intern("set-error-mode!__", procSetErrorMode, "procSetErrorMode")
+	intern("error-mode__", procErrorMode, "procErrorMode")
+	intern("shutdown-agents__", procShutdownAgents, "procShutdownAgents")
 	intern("<!__", procReceive, "procReceive")
 	intern(">!__", procSend, "procSend")
 	intern("chan__", procCreateChan, "procCreateChan")
 	intern("close!__", procCloseChan, "procCloseChan")
+	intern("channel-buffer__", procChannelBuffer, "procChannelBuffer")
+	intern("timeout__", procTimeout, "procTimeout")
+	intern("alts!__", procAlts, "procAlts")
 
 	intern("go-spew__", procGoSpew, "procGoSpew")
 	intern("verbosity-level__", procVerbosityLevel, "procVerbosityLevel")
@@ -193,4 +258,6 @@ func init() {
 	intern("infinite?__", procIsInfinite, "procIsInfinite")
 	intern("parseDouble__", procParseDouble, "procParseDouble")
 	intern("parseLong__", procParseLong, "procParseLong")
+	intern("break__", procBreak, "procBreak")
+	intern("call-position__", procCallPosition, "procCallPosition")
 }
@@ -211,15 +211,27 @@ func eatWhitespace(reader *Reader) {
 			continue
 		}
 		if (r == ';' || (r == '#' && reader.Peek() == '!')) && !FORMAT_MODE {
+			var comment bytes.Buffer
 			for r != '\n' && r != EOF {
+				if LINTER_MODE {
+					comment.WriteRune(r)
+				}
 				r = reader.Get()
 			}
+			if codes, ok := parseIgnoreComment(comment.String()); ok {
+				reader.ignore = &ignoredForm{codes: codes}
+			}
 			r = reader.Get()
 			continue
 		}
 		if r == '#' && reader.Peek() == '_' && !FORMAT_MODE {
 			reader.Get()
-			Read(reader)
+			ignore := reader.ignore
+			reader.ignore = nil
+			if obj, _ := Read(reader); LINTER_MODE && obj.Equals(KEYWORDS.jokerIgnore) {
+				ignore = &ignoredForm{}
+			}
+			reader.ignore = ignore
 			r = reader.Get()
 			continue
 		}
@@ -317,6 +329,15 @@ func scanBigFloat(orig, str string, reader *Reader) Object {
 	panic(invalidNumberError(reader, str))
 }
 
+// Decimal constants suffixed with M are read as BigDecimal.
+// Hex, octal and binary ones (e.g. 0x1.fM) are read as BigFloat.
+func scanBigDecimal(orig, str string, reader *Reader) Object {
+	if d, ok := MakeBigDecimalWithOrig(str, orig); ok {
+		return MakeReadObject(reader, d)
+	}
+	return scanBigFloat(orig, str, reader)
+}
+
 func scanInt(orig, str string, base int, reader *Reader) Object {
 	i, e := strconv.ParseInt(str, base, 0)
 	if e != nil {
@@ -394,7 +415,7 @@ func readNumber(reader *Reader) Object {
 		return scanBigInt(str, str[:b.Len()-1], 0, reader)
 	}
 	if last == 'M' {
-		return scanBigFloat(str, str[:b.Len()-1], reader)
+		return scanBigDecimal(str, str[:b.Len()-1], reader)
 	}
 	if isDouble || (!isHex && isExp) {
 		return scanFloat(str, reader)
@@ -453,7 +474,7 @@ func readIdent(reader *Reader, first rune) Object {
 			if ns == nil {
 				msg := fmt.Sprintf("Unable to resolve namespace %s in keyword %s", *sym.ns, ":"+str)
 				if LINTER_MODE {
-					printReadWarning(reader, msg)
+					printReadWarning(reader, "unresolved-namespace", msg)
 					return MakeReadObject(reader, MakeKeyword(*sym.name))
 				}
 				panic(MakeReadError(reader, msg))
@@ -556,7 +577,7 @@ func warnInvalidIdent(reader *Reader, s *string) {
 				explain = identValidationSetWhy + "; " + identValidationRangeWhy
 			}
 			msg := fmt.Sprintf("Impermissible character %q at %d in %q (%s)", r, k, *s, explain)
-			printReadWarning(reader, msg)
+			printReadWarning(reader, "invalid-character", msg)
 		}
 		k++
 	}
@@ -734,7 +755,7 @@ func readMulti(reader *Reader, previouslyRead []Object) (Object, []Object) {
 
 func readError(reader *Reader, msg string) {
 	if LINTER_MODE {
-		printReadError(reader, msg)
+		printReadError(reader, "read-error", msg)
 	} else {
 		panic(MakeReadError(reader, msg))
 	}
@@ -1138,11 +1159,43 @@ func handleNoReaderError(reader *Reader, s Symbol) Object {
 	}
 	if LINTER_MODE {
 		if DIALECT != EDN {
-			printReadWarning(reader, "No reader function for tag "+s.ToString(false))
+			printReadWarning(reader, "unknown-tag", "No reader function for tag "+s.ToString(false))
 		}
 		return readFirst(reader)
 	}
-	panic(MakeReadError(reader, "No reader function for tag "+s.ToString(false)))
+	return MakeTaggedLiteral(s, readFirst(reader))
+}
+
+func readRecord(reader *Reader, t *Type) Object {
+	switch v := readFirst(reader).(type) {
+	case Map:
+		return NewRecordFromMap(t, v)
+	case Vec:
+		values := make([]Object, v.Count())
+		for i := range values {
+			values[i] = v.At(i)
+		}
+		if len(values) != len(t.user.fields) {
+			panic(MakeReadError(reader, fmt.Sprintf("Record %s expects %d fields, got %d", t.name, len(t.user.fields), len(values))))
+		}
+		return NewInstance(t, values)
+	default:
+		panic(MakeReadError(reader, "Record literal must be a map or a vector"))
+	}
+}
+
+// callDataReader calls reader function f on form, reporting errors
+// (e.g. an invalid #inst string) as read errors at the current position.
+func callDataReader(reader *Reader, f *Var, form Object) Object {
+	defer func() {
+		if r := recover(); r != nil {
+			if err, ok := r.(*EvalError); ok {
+				panic(MakeReadError(reader, err.msg))
+			}
+			panic(r)
+		}
+	}()
+	return f.Call([]Object{form})
 }
 
 func readTagged(reader *Reader) Object {
@@ -1154,6 +1207,9 @@ func readTagged(reader *Reader) Object {
 	}
 	switch s := obj.(type) {
 	case Symbol:
+		if t := TYPES[s.name]; s.ns == nil && t != nil && t.IsRecord() {
+			return readRecord(reader, t)
+		}
 		readersVar, ok := GLOBAL_ENV.CoreNamespace.mappings[SYMBOLS.defaultDataReaders.name]
 		if !ok {
 			return handleNoReaderError(reader, s)
@@ -1166,7 +1222,7 @@ func readTagged(reader *Reader) Object {
 		if !ok {
 			return handleNoReaderError(reader, s)
 		}
-		return EnsureObjectIsVar(readFunc, "").Call([]Object{readFirst(reader)})
+		return callDataReader(reader, EnsureObjectIsVar(readFunc, ""), readFirst(reader))
 	default:
 		panic(MakeReadError(reader, "Reader tag must be a symbol"))
 	}
@@ -1389,6 +1445,16 @@ func addPrefix(obj Object, prefix string) {
 
 func Read(reader *Reader) (Object, bool) {
 	eatWhitespace(reader)
+	if ignore := reader.ignore; ignore != nil {
+		reader.ignore = nil
+		obj, multi := Read(reader)
+		if info := obj.GetInfo(); info != nil {
+			ignore.Position = info.Position
+			filename := ignore.Filename()
+			ignoredForms[filename] = append(ignoredForms[filename], *ignore)
+		}
+		return obj, multi
+	}
 	r := reader.Get()
 	pushPos(reader)
 	// This is only possible in format mode, otherwise
@@ -1505,6 +1571,10 @@ func TryRead(reader *Reader) (obj Object, err error) {
 				err = r.(error)
 			case *EvalError:
 				err = r.(error)
+			case *StackOverflowError:
+				err = r.(error)
+			case *InterruptedError:
+				err = r.(error)
 			case *ExInfo:
 				err = r.(error)
 			default:
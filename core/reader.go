@@ -14,6 +14,7 @@ type (
 		isEof          bool
 		rewind         int
 		filename       *string
+		ignore         *ignoredForm // set by an ignore comment, for the next form
 	}
 )
 
@@ -0,0 +1,353 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"unsafe"
+)
+
+type (
+	// userType holds the parts of a Type that are only present
+	// for types created at runtime by defrecord, deftype and reify.
+	userType struct {
+		fields   []Keyword
+		isRecord bool
+		// Interfaces provided by a reify form; nil for other types.
+		interfaces []*Type
+	}
+	Record struct {
+		InfoHolder
+		MetaHolder
+		rtype  *Type
+		values []Object
+		ext    Map
+	}
+	TypeInstance struct {
+		MetaHolder
+		rtype  *Type
+		values []Object
+	}
+	RecordIterator struct {
+		r       *Record
+		i       int
+		extIter MapIterator
+	}
+)
+
+func NewUserType(name string, fields []Keyword, isRecord bool) *Type {
+	var inst interface{} = (*TypeInstance)(nil)
+	kind := "Type"
+	if isRecord {
+		inst = (*Record)(nil)
+		kind = "Record"
+	}
+	meta := MakeMeta(nil, "("+kind+" defined at runtime)", "1.10")
+	meta.Add(KEYWORDS.name, MakeString(name))
+	t := &Type{
+		MetaHolder:  MetaHolder{meta},
+		name:        name,
+		reflectType: reflect.TypeOf(inst),
+		user:        &userType{fields: fields, isRecord: isRecord},
+	}
+	TYPES[STRINGS.Intern(name)] = t
+	return t
+}
+
+func (t *Type) IsRecord() bool {
+	return t.user != nil && t.user.isRecord
+}
+
+func (t *Type) fieldIndex(key Object) int {
+	if k, ok := key.(Keyword); ok {
+		for i, f := range t.user.fields {
+			if f.Equals(k) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func checkFieldCount(t *Type, values []Object) {
+	if len(values) != len(t.user.fields) {
+		panic(RT.NewError(fmt.Sprintf("Wrong number of fields (%d) passed to %s; expects %d", len(values), t.name, len(t.user.fields))))
+	}
+}
+
+func NewInstance(t *Type, values []Object) Object {
+	if t.user == nil {
+		panic(RT.NewError("Cannot create an instance of built-in type " + t.name))
+	}
+	checkFieldCount(t, values)
+	if t.user.isRecord {
+		return &Record{rtype: t, values: values}
+	}
+	return &TypeInstance{rtype: t, values: values}
+}
+
+func NewRecordFromMap(t *Type, m Map) *Record {
+	if !t.IsRecord() {
+		panic(RT.NewError(t.name + " is not a record type"))
+	}
+	res := &Record{rtype: t, values: make([]Object, len(t.user.fields))}
+	for i, f := range t.user.fields {
+		if ok, v := m.Get(f); ok {
+			res.values[i] = v
+		} else {
+			res.values[i] = NIL
+		}
+	}
+	for iter := m.Iter(); iter.HasNext(); {
+		p := iter.Next()
+		if t.fieldIndex(p.Key) < 0 {
+			res.ext = res.extWith(p.Key, p.Value)
+		}
+	}
+	return res
+}
+
+func (r *Record) extWith(key, val Object) Map {
+	if r.ext == nil {
+		return EmptyArrayMap().Assoc(key, val).(Map)
+	}
+	return r.ext.Assoc(key, val).(Map)
+}
+
+func (r *Record) ToString(escape bool) string {
+	var b bytes.Buffer
+	b.WriteString("#" + r.rtype.name)
+	b.WriteString(mapToString(r, escape))
+	return b.String()
+}
+
+func (r *Record) Equals(other interface{}) bool {
+	if r == other {
+		return true
+	}
+	o, ok := other.(*Record)
+	if !ok || o.rtype != r.rtype {
+		return false
+	}
+	for i := range r.values {
+		if !r.values[i].Equals(o.values[i]) {
+			return false
+		}
+	}
+	if r.ext == nil || o.ext == nil {
+		return r.ext == nil && o.ext == nil
+	}
+	return r.ext.Equals(o.ext)
+}
+
+func (r *Record) GetType() *Type {
+	return r.rtype
+}
+
+func (r *Record) Hash() uint32 {
+	h := getHash()
+	h.Write([]byte(r.rtype.name))
+	return hashUnordered(r.Seq(), h.Sum32())
+}
+
+func (r *Record) WithMeta(meta Map) Object {
+	res := *r
+	res.meta = SafeMerge(res.meta, meta)
+	return &res
+}
+
+func (r *Record) Count() int {
+	if r.ext == nil {
+		return len(r.values)
+	}
+	return len(r.values) + r.ext.Count()
+}
+
+func (r *Record) Seq() Seq {
+	if r.Count() == 0 {
+		return EmptyList
+	}
+	entries := make([]Object, 0, r.Count())
+	for iter := r.Iter(); iter.HasNext(); {
+		p := iter.Next()
+		entries = append(entries, NewArrayVectorFrom(p.Key, p.Value))
+	}
+	return &ArraySeq{arr: entries}
+}
+
+func (r *Record) Iter() MapIterator {
+	return &RecordIterator{r: r}
+}
+
+func (iter *RecordIterator) HasNext() bool {
+	if iter.i < len(iter.r.values) {
+		return true
+	}
+	if iter.r.ext == nil {
+		return false
+	}
+	if iter.extIter == nil {
+		iter.extIter = iter.r.ext.Iter()
+	}
+	return iter.extIter.HasNext()
+}
+
+func (iter *RecordIterator) Next() *Pair {
+	if iter.i < len(iter.r.values) {
+		i := iter.i
+		iter.i++
+		return &Pair{Key: iter.r.rtype.user.fields[i], Value: iter.r.values[i]}
+	}
+	if !iter.HasNext() {
+		panic(newIteratorError())
+	}
+	return iter.extIter.Next()
+}
+
+func (r *Record) Get(key Object) (bool, Object) {
+	if i := r.rtype.fieldIndex(key); i >= 0 {
+		return true, r.values[i]
+	}
+	if r.ext == nil {
+		return false, nil
+	}
+	return r.ext.Get(key)
+}
+
+func (r *Record) EntryAt(key Object) *ArrayVector {
+	if ok, v := r.Get(key); ok {
+		return NewArrayVectorFrom(key, v)
+	}
+	return nil
+}
+
+func (r *Record) Assoc(key, val Object) Associative {
+	res := *r
+	if i := r.rtype.fieldIndex(key); i >= 0 {
+		res.values = make([]Object, len(r.values))
+		copy(res.values, r.values)
+		res.values[i] = val
+	} else {
+		res.ext = r.extWith(key, val)
+	}
+	return &res
+}
+
+func (r *Record) Conj(obj Object) Conjable {
+	return mapConj(r, obj)
+}
+
+// Without returns a plain map when one of the record's fields
+// is removed, since the result is no longer a valid record.
+func (r *Record) Without(key Object) Map {
+	if r.rtype.fieldIndex(key) >= 0 {
+		var res Map = EmptyArrayMap()
+		for iter := r.Iter(); iter.HasNext(); {
+			p := iter.Next()
+			if !p.Key.Equals(key) {
+				res = res.Assoc(p.Key, p.Value).(Map)
+			}
+		}
+		if r.meta != nil {
+			return res.(Meta).WithMeta(r.meta).(Map)
+		}
+		return res
+	}
+	if r.ext == nil {
+		return r
+	}
+	res := *r
+	res.ext = r.ext.Without(key)
+	if res.ext.Count() == 0 {
+		res.ext = nil
+	}
+	return &res
+}
+
+func (r *Record) Keys() Seq {
+	return &MappingSeq{
+		seq: r.Seq(),
+		fn: func(obj Object) Object {
+			return obj.(Vec).Nth(0)
+		},
+	}
+}
+
+func (r *Record) Vals() Seq {
+	return &MappingSeq{
+		seq: r.Seq(),
+		fn: func(obj Object) Object {
+			return obj.(Vec).Nth(1)
+		},
+	}
+}
+
+func (r *Record) Merge(other Map) Map {
+	var res Associative = r
+	for iter := other.Iter(); iter.HasNext(); {
+		p := iter.Next()
+		res = res.Assoc(p.Key, p.Value)
+	}
+	return res.(Map)
+}
+
+func (r *Record) Call(args []Object) Object {
+	return callMap(r, args)
+}
+
+func (r *Record) Pprint(w io.Writer, indent int) int {
+	fmt.Fprint(w, "#"+r.rtype.name)
+	return pprintMap(r, w, indent+len(r.rtype.name)+1)
+}
+
+func (r *Record) kvreduce(c Callable, init Object) Object {
+	return kvreduceIter(r.Iter(), c, init)
+}
+
+func (t *TypeInstance) ToString(escape bool) string {
+	return "#object[" + t.rtype.name + "]"
+}
+
+func (t *TypeInstance) Equals(other interface{}) bool {
+	return t == other
+}
+
+func (t *TypeInstance) GetInfo() *ObjectInfo {
+	return nil
+}
+
+func (t *TypeInstance) WithInfo(info *ObjectInfo) Object {
+	return t
+}
+
+func (t *TypeInstance) GetType() *Type {
+	return t.rtype
+}
+
+func (t *TypeInstance) Hash() uint32 {
+	return HashPtr(uintptr(unsafe.Pointer(t)))
+}
+
+func (t *TypeInstance) WithMeta(meta Map) Object {
+	res := *t
+	res.meta = SafeMerge(res.meta, meta)
+	return &res
+}
+
+func getField(obj Object, key Keyword) Object {
+	switch obj := obj.(type) {
+	case *Record:
+		if ok, v := obj.Get(key); ok {
+			return v
+		}
+		return NIL
+	case *TypeInstance:
+		if i := obj.rtype.fieldIndex(key); i >= 0 {
+			return obj.values[i]
+		}
+		panic(RT.NewError(fmt.Sprintf("No field %s in type %s", key.ToString(false), obj.rtype.name)))
+	default:
+		panic(RT.NewError(obj.ToString(true) + " is not a record or a deftype instance"))
+	}
+}
@@ -0,0 +1,183 @@
+package core
+
+import (
+	"unsafe"
+)
+
+type (
+	// Reduced wraps the result of a reducing function to signal
+	// that the reduction should stop, see reduced.
+	Reduced struct {
+		val Object
+	}
+	// Volatile is a mutable box without watches or validation,
+	// used to hold the state of stateful transducers.
+	Volatile struct {
+		value Object
+	}
+)
+
+func (r *Reduced) ToString(escape bool) string {
+	return "#object[Reduced {:val " + r.val.ToString(escape) + "}]"
+}
+
+func (r *Reduced) Equals(other interface{}) bool {
+	return r == other
+}
+
+func (r *Reduced) GetInfo() *ObjectInfo {
+	return nil
+}
+
+func (r *Reduced) GetType() *Type {
+	return TYPE.Reduced
+}
+
+func (r *Reduced) Hash() uint32 {
+	return HashPtr(uintptr(unsafe.Pointer(r)))
+}
+
+func (r *Reduced) WithInfo(info *ObjectInfo) Object {
+	return r
+}
+
+func (r *Reduced) Deref() Object {
+	return r.val
+}
+
+func (v *Volatile) ToString(escape bool) string {
+	return "#object[Volatile {:val " + v.value.ToString(escape) + "}]"
+}
+
+func (v *Volatile) Equals(other interface{}) bool {
+	return v == other
+}
+
+func (v *Volatile) GetInfo() *ObjectInfo {
+	return nil
+}
+
+func (v *Volatile) GetType() *Type {
+	return TYPE.Volatile
+}
+
+func (v *Volatile) Hash() uint32 {
+	return HashPtr(uintptr(unsafe.Pointer(v)))
+}
+
+func (v *Volatile) WithInfo(info *ObjectInfo) Object {
+	return v
+}
+
+func (v *Volatile) Deref() Object {
+	return v.value
+}
+
+// reduceSeq reduces the items of s with c starting from init,
+// stopping early if c returns a Reduced value.
+func reduceSeq(c Callable, init Object, s Seq) Object {
+	acc := init
+	for ; !s.IsEmpty(); s = s.Rest() {
+		acc = c.Call([]Object{acc, s.First()})
+		if r, ok := acc.(*Reduced); ok {
+			return r.val
+		}
+	}
+	return acc
+}
+
+// reduceSeqNoInit is the two-argument version of reduce for seqs:
+// (f) for an empty seq, the only item for a seq of one item,
+// otherwise the reduction of the rest of s starting from its first item.
+func reduceSeqNoInit(c Callable, s Seq) Object {
+	if s.IsEmpty() {
+		return c.Call([]Object{})
+	}
+	return reduceSeq(c, s.First(), s.Rest())
+}
+
+// kvreduceIter is the kvreduce implementation for maps.
+func kvreduceIter(iter MapIterator, c Callable, init Object) Object {
+	res := init
+	for iter.HasNext() {
+		kv := iter.Next()
+		res = c.Call([]Object{res, kv.Key, kv.Value})
+		if r, ok := res.(*Reduced); ok {
+			return r.val
+		}
+	}
+	return res
+}
+
+// reduceMap reduces the entries of a map, as [key value] vectors,
+// without creating a seq. If keysOnly is set, only keys are passed,
+// which is how sets backed by maps are reduced.
+func reduceMap(iter MapIterator, c Callable, init Object, keysOnly bool) Object {
+	acc := init
+	for iter.HasNext() {
+		acc = c.Call([]Object{acc, mapIterItem(iter, keysOnly)})
+		if r, ok := acc.(*Reduced); ok {
+			return r.val
+		}
+	}
+	return acc
+}
+
+func reduceMapNoInit(iter MapIterator, c Callable, keysOnly bool) Object {
+	if !iter.HasNext() {
+		return c.Call([]Object{})
+	}
+	return reduceMap(iter, c, mapIterItem(iter, keysOnly), keysOnly)
+}
+
+func mapIterItem(iter MapIterator, keysOnly bool) Object {
+	p := iter.Next()
+	if keysOnly {
+		return p.Key
+	}
+	return NewArrayVectorFrom(p.Key, p.Value)
+}
+
+func (m *ArrayMap) reduce(c Callable) Object {
+	return reduceMapNoInit(m.Iter(), c, false)
+}
+
+func (m *ArrayMap) reduceInit(c Callable, init Object) Object {
+	return reduceMap(m.Iter(), c, init, false)
+}
+
+func (m *ArrayMap) kvreduce(c Callable, init Object) Object {
+	return kvreduceIter(m.Iter(), c, init)
+}
+
+func (m *HashMap) reduce(c Callable) Object {
+	return reduceMapNoInit(m.Iter(), c, false)
+}
+
+func (m *HashMap) reduceInit(c Callable, init Object) Object {
+	return reduceMap(m.Iter(), c, init, false)
+}
+
+func (m *SortedMap) reduce(c Callable) Object {
+	return reduceMapNoInit(m.Iter(), c, false)
+}
+
+func (m *SortedMap) reduceInit(c Callable, init Object) Object {
+	return reduceMap(m.Iter(), c, init, false)
+}
+
+func (set *MapSet) reduce(c Callable) Object {
+	return reduceMapNoInit(set.m.Iter(), c, true)
+}
+
+func (set *MapSet) reduceInit(c Callable, init Object) Object {
+	return reduceMap(set.m.Iter(), c, init, true)
+}
+
+func (set *SortedSet) reduce(c Callable) Object {
+	return reduceMapNoInit(set.m.Iter(), c, true)
+}
+
+func (set *SortedSet) reduceInit(c Callable, init Object) Object {
+	return reduceMap(set.m.Iter(), c, init, true)
+}
@@ -0,0 +1,206 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+type (
+	// Reified is an object created by reify. It implements all the
+	// interfaces reify supports at the Go level, but each reify form
+	// gets its own Type that only reports the interfaces the form
+	// actually provides methods for (see IsEqualOrImplements).
+	Reified struct {
+		MetaHolder
+		rtype    *Type
+		invoke   Callable
+		deref    Callable
+		seq      Callable
+		count    Callable
+		get      Callable
+		toString Callable
+		reducer  Callable
+		// Protocol methods keyed by fully qualified method symbol.
+		methods Map
+	}
+)
+
+func NewReifyType(name string, interfaces []*Type) *Type {
+	meta := MakeMeta(nil, "(Type of a reify form)", "1.10")
+	meta.Add(KEYWORDS.name, MakeString(name))
+	t := &Type{
+		MetaHolder:  MetaHolder{meta},
+		name:        name,
+		reflectType: reflect.TypeOf((*Reified)(nil)),
+		user:        &userType{interfaces: interfaces},
+	}
+	TYPES[STRINGS.Intern(name)] = t
+	return t
+}
+
+// isReifiableInterface reports whether t is one of the interfaces that
+// Reified implements in Go but a given reify form may choose not to provide.
+func isReifiableInterface(t *Type) bool {
+	switch t {
+	case TYPE.Callable, TYPE.Deref, TYPE.Seqable, TYPE.Counted, TYPE.Gettable, TYPE.Reduce:
+		return true
+	}
+	return false
+}
+
+func (u *userType) implements(abstractType *Type, concreteType *Type) bool {
+	if !concreteType.reflectType.Implements(abstractType.reflectType) {
+		return false
+	}
+	if u.interfaces == nil || !isReifiableInterface(abstractType) {
+		return true
+	}
+	for _, t := range u.interfaces {
+		if t == abstractType {
+			return true
+		}
+	}
+	return false
+}
+
+func NewReified(t *Type, methods Map) *Reified {
+	res := &Reified{rtype: t, methods: EmptyArrayMap()}
+	for iter := methods.Iter(); iter.HasNext(); {
+		p := iter.Next()
+		f := EnsureObjectIsCallable(p.Value, "reify method must be a function, got %s")
+		switch k := p.Key.(type) {
+		case Keyword:
+			switch *k.name {
+			case "invoke":
+				res.invoke = f
+			case "deref":
+				res.deref = f
+			case "seq":
+				res.seq = f
+			case "count":
+				res.count = f
+			case "get":
+				res.get = f
+			case "toString":
+				res.toString = f
+			case "reduce":
+				res.reducer = f
+			default:
+				panic(RT.NewError("Unknown reify method: " + k.ToString(false)))
+			}
+		default:
+			res.methods = res.methods.Assoc(p.Key, p.Value).(Map)
+		}
+	}
+	return res
+}
+
+func (r *Reified) notImplemented(iface string) Error {
+	return RT.NewError(fmt.Sprintf("%s does not implement %s", r.rtype.name, iface))
+}
+
+func (r *Reified) ToString(escape bool) string {
+	if r.toString == nil {
+		return "#object[" + r.rtype.name + "]"
+	}
+	return EnsureObjectIsString(r.toString.Call([]Object{r}), "toString must return a String, got %s").S
+}
+
+func (r *Reified) Equals(other interface{}) bool {
+	return r == other
+}
+
+func (r *Reified) GetInfo() *ObjectInfo {
+	return nil
+}
+
+func (r *Reified) WithInfo(info *ObjectInfo) Object {
+	return r
+}
+
+func (r *Reified) GetType() *Type {
+	return r.rtype
+}
+
+func (r *Reified) Hash() uint32 {
+	return HashPtr(uintptr(unsafe.Pointer(r)))
+}
+
+func (r *Reified) WithMeta(meta Map) Object {
+	res := *r
+	res.meta = SafeMerge(res.meta, meta)
+	return &res
+}
+
+func (r *Reified) Call(args []Object) Object {
+	if r.invoke == nil {
+		panic(r.notImplemented("Callable"))
+	}
+	return r.invoke.Call(append([]Object{r}, args...))
+}
+
+func (r *Reified) Deref() Object {
+	if r.deref == nil {
+		panic(r.notImplemented("Deref"))
+	}
+	return r.deref.Call([]Object{r})
+}
+
+func (r *Reified) Seq() Seq {
+	if r.seq == nil {
+		panic(r.notImplemented("Seqable"))
+	}
+	s := r.seq.Call([]Object{r})
+	if s.Equals(NIL) {
+		return EmptyList
+	}
+	return EnsureObjectIsSeqable(s, "seq must return a Seqable, got %s").Seq()
+}
+
+// Count falls back to counting the seq when only seq is implemented.
+func (r *Reified) Count() int {
+	if r.count == nil {
+		if r.seq == nil {
+			panic(r.notImplemented("Counted"))
+		}
+		return SeqCount(r.Seq())
+	}
+	return EnsureObjectIsInt(r.count.Call([]Object{r}), "count must return an Int, got %s").I
+}
+
+// Get treats nil returned by the get method as a missing key,
+// so that (get r k not-found) and keyword lookup work as expected.
+func (r *Reified) Get(key Object) (bool, Object) {
+	if r.get == nil {
+		return false, nil
+	}
+	v := r.get.Call([]Object{r, key})
+	if v.Equals(NIL) {
+		return false, nil
+	}
+	return true, v
+}
+
+// reduce and reduceInit fall back to reducing the seq
+// when only seq is implemented.
+func (r *Reified) reduce(c Callable) Object {
+	if r.reducer == nil {
+		return reduceSeqNoInit(c, r.Seq())
+	}
+	return r.reducer.Call([]Object{r, c.(Object)})
+}
+
+func (r *Reified) reduceInit(c Callable, init Object) Object {
+	if r.reducer == nil {
+		return reduceSeq(c, init, r.Seq())
+	}
+	return r.reducer.Call([]Object{r, c.(Object), init})
+}
+
+func (r *Reified) method(sym Symbol) Object {
+	if ok, f := r.methods.Get(sym); ok {
+		return f
+	}
+	return NIL
+}
@@ -0,0 +1,171 @@
+package core
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Sandbox restricts what evaluated code can do, so that Joker can run
+// untrusted code (e.g. as a config or rules language). Calling a var
+// the sandbox doesn't allow throws an error, and libraries can't be
+// loaded from external *ns-sources* or from outside *classpath* (as it
+// was when the sandbox was enabled).
+//
+// Namespaces and vars are allowed or denied by name ("joker.os" or
+// "joker.os/sh"); a rule for a var takes precedence over a rule for
+// its namespace. Everything not denied is allowed.
+type Sandbox struct {
+	rules map[string]bool
+	// classPath is *classpath* when the sandbox was enabled. Libraries
+	// are only loaded from there, whatever code sets *classpath* to.
+	classPath Object
+}
+
+// SANDBOX is the sandbox in effect, or nil if code is not restricted.
+var SANDBOX *Sandbox
+
+// sandboxDenied lists what NewSandbox denies: namespaces and vars that
+// access the file system, the network, other processes, or exit Joker.
+var sandboxDenied = []string{
+	"joker.bolt",
+	"joker.git",
+	"joker.http",
+	"joker.os",
+	"joker.pop3",
+	"joker.smtp",
+	"joker.core/exit",
+	"joker.core/exit__",
+	"joker.core/load-file",
+	"joker.core/load-file__",
+	"joker.core/slurp",
+	"joker.core/slurp__",
+	"joker.core/spit",
+	"joker.core/spit__",
+	"joker.filepath/abs",
+	"joker.filepath/eval-symlinks",
+	"joker.filepath/file-seq",
+	"joker.filepath/glob",
+}
+
+// NewSandbox returns a sandbox that denies side-effecting namespaces
+// and vars, such as joker.os, joker.http, slurp, spit and load-file.
+func NewSandbox() *Sandbox {
+	sb := &Sandbox{rules: make(map[string]bool)}
+	sb.Deny(sandboxDenied...)
+	return sb
+}
+
+// Allow allows the named namespaces and vars.
+func (sb *Sandbox) Allow(names ...string) {
+	for _, name := range names {
+		sb.rules[name] = true
+	}
+}
+
+// Deny denies the named namespaces and vars.
+func (sb *Sandbox) Deny(names ...string) {
+	for _, name := range names {
+		sb.rules[name] = false
+	}
+}
+
+// Allows reports whether code may use vr.
+func (sb *Sandbox) Allows(vr *Var) bool {
+	if allowed, ok := sb.rules[vr.Name()]; ok {
+		return allowed
+	}
+	if allowed, ok := sb.rules[vr.ns.Name.Name()]; ok {
+		return allowed
+	}
+	return true
+}
+
+// Configure applies the :allow and :deny entries of config, each a
+// collection of symbols naming namespaces or vars.
+func (sb *Sandbox) Configure(config Map) error {
+	for _, key := range []string{"allow", "deny"} {
+		ok, names := config.Get(MakeKeyword(key))
+		if !ok {
+			continue
+		}
+		seqable, ok := names.(Seqable)
+		if !ok {
+			return errors.New(":" + key + " value must be a vector, got " + names.GetType().ToString(false))
+		}
+		for s := seqable.Seq(); !s.IsEmpty(); s = s.Rest() {
+			sym, ok := s.First().(Symbol)
+			if !ok {
+				return errors.New(":" + key + " elements must be symbols, got " + s.First().GetType().ToString(false))
+			}
+			if key == "allow" {
+				sb.Allow(sym.ToString(false))
+			} else {
+				sb.Deny(sym.ToString(false))
+			}
+		}
+	}
+	return nil
+}
+
+// ReadSandboxConfig reads a sandbox configuration map (see
+// Sandbox.Configure) from the named file.
+func ReadSandboxConfig(filename string) (Map, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	config, err := TryRead(NewReader(bufio.NewReader(f), filename))
+	if err != nil {
+		return nil, err
+	}
+	m, ok := config.(Map)
+	if !ok {
+		return nil, errors.New("config root object must be a map, got " + config.GetType().ToString(false))
+	}
+	return m, nil
+}
+
+// EnableSandbox restricts all code evaluated from now on by sb.
+// Vars that sb doesn't allow, including those of namespaces that are
+// loaded later, are replaced by functions that throw when called.
+func EnableSandbox(sb *Sandbox) {
+	SANDBOX = sb
+	sb.classPath = GLOBAL_ENV.classPath.Value
+	for _, ns := range GLOBAL_ENV.Namespaces {
+		sb.apply(ns)
+	}
+}
+
+func (sb *Sandbox) apply(ns *Namespace) {
+	for _, vr := range ns.mappings {
+		if vr.ns == ns && !sb.Allows(vr) {
+			vr.Value = sandboxedProc(vr.Name())
+		}
+	}
+}
+
+func sandboxedProc(name string) Proc {
+	return Proc{
+		Fn: func(args []Object) Object {
+			panic(RT.NewError(name + " is not allowed in sandbox mode"))
+		},
+		Name: name,
+	}
+}
+
+// checkSandboxLibPath panics if the sandbox is enabled and path is not
+// where lib would normally be found, so that require can't be used to
+// read arbitrary files.
+func checkSandboxLibPath(lib, path string) {
+	if SANDBOX == nil {
+		return
+	}
+	libPath := filepath.Join(strings.Split(lib, ".")...) + ".joke"
+	if path != libPath && !strings.HasSuffix(path, string(filepath.Separator)+libPath) {
+		panic(RT.NewError("Loading " + path + " is not allowed in sandbox mode"))
+	}
+}
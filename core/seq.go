@@ -131,6 +131,7 @@ func (seq *LazySeq) Seq() Seq {
 
 func (seq *LazySeq) realize() {
 	if seq.seq == nil {
+		RT.step()
 		seq.seq = EnsureObjectIsSeqable(seq.fn.Call([]Object{}), "").Seq()
 	}
 }
@@ -69,11 +69,32 @@ func (set *MapSet) Equals(other interface{}) bool {
 	switch otherSet := other.(type) {
 	case *MapSet:
 		return set.m.Equals(otherSet.m)
+	case Set:
+		return setEquals(otherSet, set)
 	default:
 		return false
 	}
 }
 
+// setEquals reports whether other is a Set with the same elements as set.
+// Membership is checked in other, so callers pass the set that is
+// cheaper to look elements up in as other.
+func setEquals(set Set, other interface{}) bool {
+	otherSet, ok := other.(Set)
+	if !ok {
+		return false
+	}
+	if set.(Counted).Count() != otherSet.(Counted).Count() {
+		return false
+	}
+	for s := set.(Seqable).Seq(); !s.IsEmpty(); s = s.Rest() {
+		if ok, _ := otherSet.Get(s.First()); !ok {
+			return false
+		}
+	}
+	return true
+}
+
 func (set *MapSet) Get(key Object) (bool, Object) {
 	if ok, _ := set.m.Get(key); ok {
 		return true, key
@@ -0,0 +1,552 @@
+package core
+
+import (
+	"io"
+)
+
+type (
+	Sorted interface {
+		CompareKeys(a, b Object) int
+		EntryKey(entry Object) Object
+		SortedSeq(ascending bool) Seq
+		SeqFrom(key Object, ascending bool) Seq
+	}
+	// SortedMap is a persistent red-black tree, a port of Clojure's
+	// PersistentTreeMap. Keys are ordered by comp, or by compare
+	// when comp is nil.
+	SortedMap struct {
+		InfoHolder
+		MetaHolder
+		comp  Comparator
+		tree  *rbNode
+		count int
+	}
+	rbNode struct {
+		key   Object
+		val   Object
+		left  *rbNode
+		right *rbNode
+		red   bool
+	}
+	rbStack struct {
+		node *rbNode
+		next *rbStack
+	}
+	SortedMapSeq struct {
+		InfoHolder
+		MetaHolder
+		stack     *rbStack
+		ascending bool
+		keysOnly  bool
+	}
+	SortedMapIterator struct {
+		stack []*rbNode
+	}
+)
+
+func isRed(n *rbNode) bool {
+	return n != nil && n.red
+}
+
+func isBlack(n *rbNode) bool {
+	return n != nil && !n.red
+}
+
+func red(key, val Object, left, right *rbNode) *rbNode {
+	return &rbNode{key: key, val: val, left: left, right: right, red: true}
+}
+
+func black(key, val Object, left, right *rbNode) *rbNode {
+	return &rbNode{key: key, val: val, left: left, right: right}
+}
+
+func (n *rbNode) blacken() *rbNode {
+	if !n.red {
+		return n
+	}
+	return black(n.key, n.val, n.left, n.right)
+}
+
+func (n *rbNode) redden() *rbNode {
+	if n.red {
+		panic(RT.NewError("Invariant violation"))
+	}
+	return red(n.key, n.val, n.left, n.right)
+}
+
+func (n *rbNode) replace(key, val Object, left, right *rbNode) *rbNode {
+	return &rbNode{key: key, val: val, left: left, right: right, red: n.red}
+}
+
+func (n *rbNode) addLeft(ins *rbNode) *rbNode {
+	if n.red {
+		return red(n.key, n.val, ins, n.right)
+	}
+	return ins.balanceLeft(n)
+}
+
+func (n *rbNode) addRight(ins *rbNode) *rbNode {
+	if n.red {
+		return red(n.key, n.val, n.left, ins)
+	}
+	return ins.balanceRight(n)
+}
+
+func (n *rbNode) balanceLeft(parent *rbNode) *rbNode {
+	if n.red {
+		if isRed(n.left) {
+			return red(n.key, n.val, n.left.blacken(), black(parent.key, parent.val, n.right, parent.right))
+		}
+		if isRed(n.right) {
+			return red(n.right.key, n.right.val, black(n.key, n.val, n.left, n.right.left), black(parent.key, parent.val, n.right.right, parent.right))
+		}
+	}
+	return black(parent.key, parent.val, n, parent.right)
+}
+
+func (n *rbNode) balanceRight(parent *rbNode) *rbNode {
+	if n.red {
+		if isRed(n.right) {
+			return red(n.key, n.val, black(parent.key, parent.val, parent.left, n.left), n.right.blacken())
+		}
+		if isRed(n.left) {
+			return red(n.left.key, n.left.val, black(parent.key, parent.val, parent.left, n.left.left), black(n.key, n.val, n.left.right, n.right))
+		}
+	}
+	return black(parent.key, parent.val, parent.left, n)
+}
+
+func leftBalance(key, val Object, ins, right *rbNode) *rbNode {
+	if isRed(ins) && isRed(ins.left) {
+		return red(ins.key, ins.val, ins.left.blacken(), black(key, val, ins.right, right))
+	}
+	if isRed(ins) && isRed(ins.right) {
+		return red(ins.right.key, ins.right.val, black(ins.key, ins.val, ins.left, ins.right.left), black(key, val, ins.right.right, right))
+	}
+	return black(key, val, ins, right)
+}
+
+func rightBalance(key, val Object, left, ins *rbNode) *rbNode {
+	if isRed(ins) && isRed(ins.right) {
+		return red(ins.key, ins.val, black(key, val, left, ins.left), ins.right.blacken())
+	}
+	if isRed(ins) && isRed(ins.left) {
+		return red(ins.left.key, ins.left.val, black(key, val, left, ins.left.left), black(ins.key, ins.val, ins.left.right, ins.right))
+	}
+	return black(key, val, left, ins)
+}
+
+func balanceLeftDel(key, val Object, del, right *rbNode) *rbNode {
+	if isRed(del) {
+		return red(key, val, del.blacken(), right)
+	}
+	if isBlack(right) {
+		return rightBalance(key, val, del, right.redden())
+	}
+	if isRed(right) && isBlack(right.left) {
+		return red(right.left.key, right.left.val, black(key, val, del, right.left.left), rightBalance(right.key, right.val, right.left.right, right.right.redden()))
+	}
+	panic(RT.NewError("Invariant violation"))
+}
+
+func balanceRightDel(key, val Object, left, del *rbNode) *rbNode {
+	if isRed(del) {
+		return red(key, val, left, del.blacken())
+	}
+	if isBlack(left) {
+		return leftBalance(key, val, left.redden(), del)
+	}
+	if isRed(left) && isBlack(left.right) {
+		return red(left.right.key, left.right.val, leftBalance(left.key, left.val, left.left.redden(), left.right.left), black(key, val, left.right.right, del))
+	}
+	panic(RT.NewError("Invariant violation"))
+}
+
+// appendNodes joins the two subtrees of a removed node.
+func appendNodes(left, right *rbNode) *rbNode {
+	if left == nil {
+		return right
+	}
+	if right == nil {
+		return left
+	}
+	if left.red {
+		if right.red {
+			app := appendNodes(left.right, right.left)
+			if isRed(app) {
+				return red(app.key, app.val, red(left.key, left.val, left.left, app.left), red(right.key, right.val, app.right, right.right))
+			}
+			return red(left.key, left.val, left.left, red(right.key, right.val, app, right.right))
+		}
+		return red(left.key, left.val, left.left, appendNodes(left.right, right))
+	}
+	if right.red {
+		return red(right.key, right.val, appendNodes(left, right.left), right.right)
+	}
+	app := appendNodes(left.right, right.left)
+	if isRed(app) {
+		return red(app.key, app.val, black(left.key, left.val, left.left, app.left), black(right.key, right.val, app.right, right.right))
+	}
+	return balanceLeftDel(left.key, left.val, left.left, black(right.key, right.val, app, right.right))
+}
+
+func NewSortedMap(comp Comparator) *SortedMap {
+	return &SortedMap{comp: comp}
+}
+
+func (m *SortedMap) CompareKeys(a, b Object) int {
+	if m.comp == nil {
+		return compareObjects(a, b)
+	}
+	return m.comp.Compare(a, b)
+}
+
+func (m *SortedMap) EntryKey(entry Object) Object {
+	return EnsureObjectIsVec(entry, "Sorted map entry must be a vector, got %s").Nth(0)
+}
+
+func (m *SortedMap) find(key Object) *rbNode {
+	t := m.tree
+	for t != nil {
+		c := m.CompareKeys(key, t.key)
+		if c == 0 {
+			return t
+		}
+		if c < 0 {
+			t = t.left
+		} else {
+			t = t.right
+		}
+	}
+	return nil
+}
+
+// add returns nil and sets found if the key is already present.
+func (m *SortedMap) add(t *rbNode, key, val Object, found *Box) *rbNode {
+	if t == nil {
+		return red(key, val, nil, nil)
+	}
+	c := m.CompareKeys(key, t.key)
+	if c == 0 {
+		found.val = t
+		return nil
+	}
+	if c < 0 {
+		ins := m.add(t.left, key, val, found)
+		if ins == nil {
+			return nil
+		}
+		return t.addLeft(ins)
+	}
+	ins := m.add(t.right, key, val, found)
+	if ins == nil {
+		return nil
+	}
+	return t.addRight(ins)
+}
+
+func (m *SortedMap) replace(t *rbNode, key, val Object) *rbNode {
+	c := m.CompareKeys(key, t.key)
+	switch {
+	case c == 0:
+		return t.replace(t.key, val, t.left, t.right)
+	case c < 0:
+		return t.replace(t.key, t.val, m.replace(t.left, key, val), t.right)
+	default:
+		return t.replace(t.key, t.val, t.left, m.replace(t.right, key, val))
+	}
+}
+
+// remove returns nil and leaves found unset if the key is not present.
+func (m *SortedMap) remove(t *rbNode, key Object, found *Box) *rbNode {
+	if t == nil {
+		return nil
+	}
+	c := m.CompareKeys(key, t.key)
+	if c == 0 {
+		found.val = t
+		return appendNodes(t.left, t.right)
+	}
+	var del *rbNode
+	if c < 0 {
+		del = m.remove(t.left, key, found)
+	} else {
+		del = m.remove(t.right, key, found)
+	}
+	if del == nil && found.val == nil {
+		return nil
+	}
+	if c < 0 {
+		if isBlack(t.left) {
+			return balanceLeftDel(t.key, t.val, del, t.right)
+		}
+		return red(t.key, t.val, del, t.right)
+	}
+	if isBlack(t.right) {
+		return balanceRightDel(t.key, t.val, t.left, del)
+	}
+	return red(t.key, t.val, t.left, del)
+}
+
+func (m *SortedMap) WithMeta(meta Map) Object {
+	res := *m
+	res.meta = SafeMerge(res.meta, meta)
+	return &res
+}
+
+func (m *SortedMap) ToString(escape bool) string {
+	return mapToString(m, escape)
+}
+
+func (m *SortedMap) Equals(other interface{}) bool {
+	return mapEquals(m, other)
+}
+
+func (m *SortedMap) GetType() *Type {
+	return TYPE.SortedMap
+}
+
+func (m *SortedMap) Hash() uint32 {
+	return hashUnordered(m.Seq(), 1)
+}
+
+func (m *SortedMap) Count() int {
+	return m.count
+}
+
+func (m *SortedMap) Get(key Object) (bool, Object) {
+	if t := m.find(key); t != nil {
+		return true, t.val
+	}
+	return false, nil
+}
+
+func (m *SortedMap) EntryAt(key Object) *ArrayVector {
+	if t := m.find(key); t != nil {
+		return NewArrayVectorFrom(t.key, t.val)
+	}
+	return nil
+}
+
+func (m *SortedMap) Assoc(key, val Object) Associative {
+	found := &Box{}
+	t := m.add(m.tree, key, val, found)
+	res := &SortedMap{comp: m.comp}
+	res.meta = m.meta
+	if t == nil {
+		if found.val.(*rbNode).val == val {
+			return m
+		}
+		res.tree = m.replace(m.tree, key, val)
+		res.count = m.count
+		return res
+	}
+	res.tree = t.blacken()
+	res.count = m.count + 1
+	return res
+}
+
+func (m *SortedMap) Without(key Object) Map {
+	found := &Box{}
+	t := m.remove(m.tree, key, found)
+	if found.val == nil {
+		return m
+	}
+	res := &SortedMap{comp: m.comp, count: m.count - 1}
+	res.meta = m.meta
+	if t != nil {
+		res.tree = t.blacken()
+	}
+	return res
+}
+
+func (m *SortedMap) Conj(obj Object) Conjable {
+	return mapConj(m, obj)
+}
+
+func (m *SortedMap) Merge(other Map) Map {
+	var res Associative = m
+	for iter := other.Iter(); iter.HasNext(); {
+		p := iter.Next()
+		res = res.Assoc(p.Key, p.Value)
+	}
+	return res.(Map)
+}
+
+func (m *SortedMap) Seq() Seq {
+	return m.SortedSeq(true)
+}
+
+func (m *SortedMap) Rseq() Seq {
+	return m.SortedSeq(false)
+}
+
+func (m *SortedMap) SortedSeq(ascending bool) Seq {
+	return m.sortedSeq(ascending, false)
+}
+
+func (m *SortedMap) sortedSeq(ascending bool, keysOnly bool) Seq {
+	if m.count == 0 {
+		return EmptyList
+	}
+	return &SortedMapSeq{stack: pushNodes(m.tree, nil, ascending), ascending: ascending, keysOnly: keysOnly}
+}
+
+func (m *SortedMap) SeqFrom(key Object, ascending bool) Seq {
+	return m.seqFrom(key, ascending, false)
+}
+
+func (m *SortedMap) seqFrom(key Object, ascending bool, keysOnly bool) Seq {
+	var stack *rbStack
+	for t := m.tree; t != nil; {
+		c := m.CompareKeys(key, t.key)
+		if c == 0 {
+			stack = &rbStack{node: t, next: stack}
+			break
+		}
+		if ascending == (c < 0) {
+			stack = &rbStack{node: t, next: stack}
+		}
+		if c < 0 {
+			t = t.left
+		} else {
+			t = t.right
+		}
+	}
+	if stack == nil {
+		return EmptyList
+	}
+	return &SortedMapSeq{stack: stack, ascending: ascending, keysOnly: keysOnly}
+}
+
+func (m *SortedMap) Keys() Seq {
+	return m.sortedSeq(true, true)
+}
+
+func (m *SortedMap) Vals() Seq {
+	return &MappingSeq{
+		seq: m.Seq(),
+		fn: func(obj Object) Object {
+			return obj.(Vec).Nth(1)
+		},
+	}
+}
+
+func (m *SortedMap) Iter() MapIterator {
+	return &SortedMapIterator{stack: pushIterNodes(m.tree, nil)}
+}
+
+func (m *SortedMap) Call(args []Object) Object {
+	return callMap(m, args)
+}
+
+func (m *SortedMap) Empty() Collection {
+	return NewSortedMap(m.comp)
+}
+
+func (m *SortedMap) Pprint(w io.Writer, indent int) int {
+	return pprintMap(m, w, indent)
+}
+
+func (m *SortedMap) kvreduce(c Callable, init Object) Object {
+	return kvreduceIter(m.Iter(), c, init)
+}
+
+func pushNodes(t *rbNode, stack *rbStack, ascending bool) *rbStack {
+	for t != nil {
+		stack = &rbStack{node: t, next: stack}
+		if ascending {
+			t = t.left
+		} else {
+			t = t.right
+		}
+	}
+	return stack
+}
+
+func pushIterNodes(t *rbNode, stack []*rbNode) []*rbNode {
+	for t != nil {
+		stack = append(stack, t)
+		t = t.left
+	}
+	return stack
+}
+
+func (iter *SortedMapIterator) HasNext() bool {
+	return len(iter.stack) > 0
+}
+
+func (iter *SortedMapIterator) Next() *Pair {
+	if len(iter.stack) == 0 {
+		panic(newIteratorError())
+	}
+	t := iter.stack[len(iter.stack)-1]
+	iter.stack = pushIterNodes(t.right, iter.stack[:len(iter.stack)-1])
+	return &Pair{Key: t.key, Value: t.val}
+}
+
+func (seq *SortedMapSeq) WithMeta(meta Map) Object {
+	res := *seq
+	res.meta = SafeMerge(res.meta, meta)
+	return &res
+}
+
+func (seq *SortedMapSeq) Seq() Seq {
+	return seq
+}
+
+func (seq *SortedMapSeq) Equals(other interface{}) bool {
+	return IsSeqEqual(seq, other)
+}
+
+func (seq *SortedMapSeq) ToString(escape bool) string {
+	return SeqToString(seq, escape)
+}
+
+func (seq *SortedMapSeq) Pprint(w io.Writer, indent int) int {
+	return pprintSeq(seq, w, indent)
+}
+
+func (seq *SortedMapSeq) Format(w io.Writer, indent int) int {
+	return formatSeq(seq, w, indent)
+}
+
+func (seq *SortedMapSeq) GetType() *Type {
+	return TYPE.SortedMapSeq
+}
+
+func (seq *SortedMapSeq) Hash() uint32 {
+	return hashOrdered(seq)
+}
+
+func (seq *SortedMapSeq) First() Object {
+	t := seq.stack.node
+	if seq.keysOnly {
+		return t.key
+	}
+	return NewArrayVectorFrom(t.key, t.val)
+}
+
+func (seq *SortedMapSeq) Rest() Seq {
+	t := seq.stack.node
+	var next *rbStack
+	if seq.ascending {
+		next = pushNodes(t.right, seq.stack.next, true)
+	} else {
+		next = pushNodes(t.left, seq.stack.next, false)
+	}
+	if next == nil {
+		return EmptyList
+	}
+	return &SortedMapSeq{stack: next, ascending: seq.ascending, keysOnly: seq.keysOnly}
+}
+
+func (seq *SortedMapSeq) IsEmpty() bool {
+	return false
+}
+
+func (seq *SortedMapSeq) Cons(obj Object) Seq {
+	return &ConsSeq{first: obj, rest: seq}
+}
+
+func (seq *SortedMapSeq) sequential() {}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+type (
+	// SortedSet is a persistent set backed by a SortedMap
+	// whose keys are the elements of the set.
+	SortedSet struct {
+		InfoHolder
+		MetaHolder
+		m *SortedMap
+	}
+)
+
+func NewSortedSet(comp Comparator) *SortedSet {
+	return &SortedSet{m: NewSortedMap(comp)}
+}
+
+func (set *SortedSet) WithMeta(meta Map) Object {
+	res := *set
+	res.meta = SafeMerge(res.meta, meta)
+	return &res
+}
+
+func (set *SortedSet) Disjoin(key Object) Set {
+	m := set.m.Without(key).(*SortedMap)
+	if m == set.m {
+		return set
+	}
+	return &SortedSet{MetaHolder: set.MetaHolder, m: m}
+}
+
+func (set *SortedSet) Conj(obj Object) Conjable {
+	m := set.m.Assoc(obj, obj).(*SortedMap)
+	if m == set.m {
+		return set
+	}
+	return &SortedSet{MetaHolder: set.MetaHolder, m: m}
+}
+
+func (set *SortedSet) ToString(escape bool) string {
+	var b bytes.Buffer
+	b.WriteString("#{")
+	for iter := iter(set.Seq()); iter.HasNext(); {
+		b.WriteString(iter.Next().ToString(escape))
+		if iter.HasNext() {
+			b.WriteRune(' ')
+		}
+	}
+	b.WriteRune('}')
+	return b.String()
+}
+
+func (set *SortedSet) Equals(other interface{}) bool {
+	return setEquals(set, other)
+}
+
+func (set *SortedSet) Get(key Object) (bool, Object) {
+	if t := set.m.find(key); t != nil {
+		return true, t.key
+	}
+	return false, nil
+}
+
+func (set *SortedSet) GetType() *Type {
+	return TYPE.SortedSet
+}
+
+func (set *SortedSet) Hash() uint32 {
+	return hashUnordered(set.Seq(), 2)
+}
+
+func (set *SortedSet) Seq() Seq {
+	return set.m.Keys()
+}
+
+func (set *SortedSet) Rseq() Seq {
+	return set.m.sortedSeq(false, true)
+}
+
+func (set *SortedSet) CompareKeys(a, b Object) int {
+	return set.m.CompareKeys(a, b)
+}
+
+func (set *SortedSet) EntryKey(entry Object) Object {
+	return entry
+}
+
+func (set *SortedSet) SortedSeq(ascending bool) Seq {
+	return set.m.sortedSeq(ascending, true)
+}
+
+func (set *SortedSet) SeqFrom(key Object, ascending bool) Seq {
+	return set.m.seqFrom(key, ascending, true)
+}
+
+func (set *SortedSet) Count() int {
+	return set.m.Count()
+}
+
+func (set *SortedSet) Call(args []Object) Object {
+	CheckArity(args, 1, 1)
+	if ok, v := set.Get(args[0]); ok {
+		return v
+	}
+	return NIL
+}
+
+func (set *SortedSet) Empty() Collection {
+	return NewSortedSet(set.m.comp)
+}
+
+func (set *SortedSet) Pprint(w io.Writer, indent int) int {
+	i := indent + 1
+	fmt.Fprint(w, "#{")
+	for iter := iter(set.Seq()); iter.HasNext(); {
+		i = pprintObject(iter.Next(), indent+2, w)
+		if iter.HasNext() {
+			fmt.Fprint(w, "\n")
+			writeIndent(w, indent+2)
+		}
+	}
+	fmt.Fprint(w, "}")
+	return i + 1
+}
@@ -0,0 +1,47 @@
+package core
+
+type (
+	// TaggedLiteral is what the reader returns for a tagged literal
+	// (e.g. #foo/bar [1 2]) that has no reader function.
+	TaggedLiteral struct {
+		InfoHolder
+		tag  Symbol
+		form Object
+	}
+)
+
+func MakeTaggedLiteral(tag Symbol, form Object) *TaggedLiteral {
+	return &TaggedLiteral{tag: tag, form: form}
+}
+
+func (t *TaggedLiteral) ToString(escape bool) string {
+	return "#" + t.tag.ToString(false) + " " + t.form.ToString(escape)
+}
+
+func (t *TaggedLiteral) Equals(other interface{}) bool {
+	switch other := other.(type) {
+	case *TaggedLiteral:
+		return t.tag.Equals(other.tag) && t.form.Equals(other.form)
+	default:
+		return false
+	}
+}
+
+func (t *TaggedLiteral) GetType() *Type {
+	return TYPE.TaggedLiteral
+}
+
+func (t *TaggedLiteral) Hash() uint32 {
+	return 31*t.tag.Hash() + t.form.Hash()
+}
+
+func (t *TaggedLiteral) Get(key Object) (bool, Object) {
+	switch {
+	case key.Equals(KEYWORDS.tag):
+		return true, t.tag
+	case key.Equals(KEYWORDS.form):
+		return true, t.form
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,562 @@
+package core
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+type (
+	// Edit is shared by a transient collection and the nodes it creates.
+	// Only nodes that belong to the transient's Edit are updated in place,
+	// and only until persistent! is called; all other nodes are copied
+	// on first write.
+	Edit struct {
+		persisted bool
+	}
+	Transient interface {
+		Object
+		Counted
+		Conj(obj Object) Transient
+		Persistent() Object
+	}
+	TransientAssociative interface {
+		Transient
+		Gettable
+		Assoc(key, val Object) Transient
+	}
+	TransientMap interface {
+		TransientAssociative
+		Without(key Object) Transient
+	}
+	TransientVector struct {
+		edit *Edit
+		// Backing arrays of the nodes created by this transient.
+		// Vector nodes are plain slices, so ownership is tracked here
+		// rather than in the nodes themselves.
+		owned map[*interface{}]struct{}
+		root  []interface{}
+		tail  []interface{}
+		count int
+		shift uint
+	}
+	TransientArrayMap struct {
+		edit *Edit
+		arr  []Object
+	}
+	TransientHashMap struct {
+		edit  *Edit
+		root  Node
+		count int
+	}
+	TransientSet struct {
+		m TransientMap
+	}
+)
+
+func (e *Edit) ensureEditable() {
+	if e.persisted {
+		panic(RT.NewError("Transient used after persistent! call"))
+	}
+}
+
+// IsEditable reports whether a transient can be created from coll.
+func IsEditable(coll Object) bool {
+	switch coll.(type) {
+	case *Vector, *ArrayVector, *ArrayMap, *HashMap, *MapSet:
+		return true
+	}
+	return false
+}
+
+func NewTransient(coll Object) Transient {
+	switch coll := coll.(type) {
+	case *Vector:
+		return coll.AsTransient()
+	case *ArrayVector:
+		return NewVectorFrom(coll.arr...).AsTransient()
+	case *ArrayMap:
+		return coll.AsTransient()
+	case *HashMap:
+		return coll.AsTransient()
+	case *MapSet:
+		return coll.AsTransient()
+	default:
+		panic(RT.NewError("Cannot create a transient from " + coll.GetType().ToString(false)))
+	}
+}
+
+func transientToString(t Transient) string {
+	return "#object[" + t.GetType().ToString(false) + "]"
+}
+
+func transientMapConj(m TransientAssociative, obj Object) Transient {
+	switch obj := obj.(type) {
+	case Vec:
+		if obj.Count() != 2 {
+			panic(RT.NewError("Vector argument to map's conj must be a vector with two elements"))
+		}
+		return m.Assoc(obj.At(0), obj.At(1))
+	case Map:
+		var res Transient = m
+		for iter := obj.Iter(); iter.HasNext(); {
+			p := iter.Next()
+			res = res.(TransientAssociative).Assoc(p.Key, p.Value)
+		}
+		return res
+	default:
+		panic(RT.NewError("Argument to map's conj must be a vector with two elements or a map"))
+	}
+}
+
+func (v *Vector) AsTransient() *TransientVector {
+	tail := make([]interface{}, len(v.tail), 32)
+	copy(tail, v.tail)
+	return &TransientVector{
+		edit:  &Edit{},
+		owned: make(map[*interface{}]struct{}),
+		root:  v.root,
+		tail:  tail,
+		count: v.count,
+		shift: v.shift,
+	}
+}
+
+func (t *TransientVector) ToString(escape bool) string {
+	return transientToString(t)
+}
+
+func (t *TransientVector) Equals(other interface{}) bool {
+	return t == other
+}
+
+func (t *TransientVector) GetInfo() *ObjectInfo {
+	return nil
+}
+
+func (t *TransientVector) WithInfo(info *ObjectInfo) Object {
+	return t
+}
+
+func (t *TransientVector) GetType() *Type {
+	return TYPE.TransientVector
+}
+
+func (t *TransientVector) Hash() uint32 {
+	return HashPtr(uintptr(unsafe.Pointer(t)))
+}
+
+func (t *TransientVector) Count() int {
+	t.edit.ensureEditable()
+	return t.count
+}
+
+func (t *TransientVector) own(node []interface{}) []interface{} {
+	t.owned[&node[0]] = struct{}{}
+	return node
+}
+
+func (t *TransientVector) editableNode(node []interface{}) []interface{} {
+	if _, ok := t.owned[&node[0]]; ok {
+		return node
+	}
+	return t.own(clone(node))
+}
+
+func (t *TransientVector) tailoff() int {
+	if t.count < 32 {
+		return 0
+	}
+	return ((t.count - 1) >> 5) << 5
+}
+
+func (t *TransientVector) arrayFor(i int) []interface{} {
+	if i >= t.tailoff() {
+		return t.tail
+	}
+	node := t.root
+	for level := t.shift; level > 0; level -= 5 {
+		node = node[(i>>level)&0x01F].([]interface{})
+	}
+	return node
+}
+
+func (t *TransientVector) Get(key Object) (bool, Object) {
+	t.edit.ensureEditable()
+	if key, ok := key.(Int); ok && key.I >= 0 && key.I < t.count {
+		return true, t.arrayFor(key.I)[key.I&0x01F].(Object)
+	}
+	return false, nil
+}
+
+func (t *TransientVector) Nth(i int) Object {
+	t.edit.ensureEditable()
+	if i < 0 || i >= t.count {
+		panic(RT.NewError(fmt.Sprintf("Index %d is out of bounds [0..%d]", i, t.count-1)))
+	}
+	return t.arrayFor(i)[i&0x01F].(Object)
+}
+
+func (t *TransientVector) TryNth(i int, d Object) Object {
+	t.edit.ensureEditable()
+	if i < 0 || i >= t.count {
+		return d
+	}
+	return t.arrayFor(i)[i&0x01F].(Object)
+}
+
+func (t *TransientVector) newPath(level uint, node []interface{}) []interface{} {
+	if level == 0 {
+		return node
+	}
+	res := t.own(make([]interface{}, 32))
+	res[0] = t.newPath(level-5, node)
+	return res
+}
+
+func (t *TransientVector) pushTail(level uint, parent []interface{}, tailNode []interface{}) []interface{} {
+	subidx := ((t.count - 1) >> level) & 0x01F
+	res := t.editableNode(parent)
+	var nodeToInsert []interface{}
+	if level == 5 {
+		nodeToInsert = tailNode
+	} else if child := res[subidx]; child != nil {
+		nodeToInsert = t.pushTail(level-5, child.([]interface{}), tailNode)
+	} else {
+		nodeToInsert = t.newPath(level-5, tailNode)
+	}
+	res[subidx] = nodeToInsert
+	return res
+}
+
+func (t *TransientVector) Conj(obj Object) Transient {
+	t.edit.ensureEditable()
+	if t.count-t.tailoff() < 32 {
+		t.tail = append(t.tail, obj)
+		t.count++
+		return t
+	}
+	tailNode := t.own(t.tail)
+	if (t.count >> 5) > (1 << t.shift) {
+		root := t.own(make([]interface{}, 32))
+		root[0] = t.root
+		root[1] = t.newPath(t.shift, tailNode)
+		t.root = root
+		t.shift += 5
+	} else {
+		t.root = t.pushTail(t.shift, t.root, tailNode)
+	}
+	t.tail = make([]interface{}, 1, 32)
+	t.tail[0] = obj
+	t.count++
+	return t
+}
+
+func (t *TransientVector) doAssoc(level uint, node []interface{}, i int, val Object) []interface{} {
+	res := t.editableNode(node)
+	if level == 0 {
+		res[i&0x01F] = val
+	} else {
+		subidx := (i >> level) & 0x01F
+		res[subidx] = t.doAssoc(level-5, res[subidx].([]interface{}), i, val)
+	}
+	return res
+}
+
+func (t *TransientVector) AssocN(i int, val Object) Transient {
+	t.edit.ensureEditable()
+	if i < 0 || i > t.count {
+		panic(RT.NewError((fmt.Sprintf("Index %d is out of bounds [0..%d]", i, t.count))))
+	}
+	if i == t.count {
+		return t.Conj(val)
+	}
+	if i >= t.tailoff() {
+		t.tail[i&0x01F] = val
+	} else {
+		t.root = t.doAssoc(t.shift, t.root, i, val)
+	}
+	return t
+}
+
+func (t *TransientVector) Assoc(key, val Object) Transient {
+	return t.AssocN(assertInteger(key), val)
+}
+
+func (t *TransientVector) popTail(level uint, node []interface{}) []interface{} {
+	subidx := ((t.count - 2) >> level) & 0x01F
+	if level > 5 {
+		newChild := t.popTail(level-5, node[subidx].([]interface{}))
+		if newChild == nil && subidx == 0 {
+			return nil
+		}
+		res := t.editableNode(node)
+		if newChild == nil {
+			res[subidx] = nil
+		} else {
+			res[subidx] = newChild
+		}
+		return res
+	} else if subidx == 0 {
+		return nil
+	}
+	res := t.editableNode(node)
+	res[subidx] = nil
+	return res
+}
+
+func (t *TransientVector) Pop() Transient {
+	t.edit.ensureEditable()
+	if t.count == 0 {
+		panic(RT.NewError("Can't pop empty vector"))
+	}
+	if t.count == 1 || t.count-t.tailoff() > 1 {
+		t.tail[len(t.tail)-1] = nil
+		t.tail = t.tail[:len(t.tail)-1]
+		t.count--
+		return t
+	}
+	newTail := make([]interface{}, 32)
+	copy(newTail, t.arrayFor(t.count-2))
+	newRoot := t.popTail(t.shift, t.root)
+	if newRoot == nil {
+		newRoot = empty_node
+	}
+	if t.shift > 5 && newRoot[1] == nil {
+		newRoot = newRoot[0].([]interface{})
+		t.shift -= 5
+	}
+	t.root = newRoot
+	t.tail = newTail
+	t.count--
+	return t
+}
+
+func (t *TransientVector) Persistent() Object {
+	t.edit.ensureEditable()
+	t.edit.persisted = true
+	t.owned = nil
+	return &Vector{count: t.count, shift: t.shift, root: t.root, tail: t.tail}
+}
+
+func (m *ArrayMap) AsTransient() *TransientArrayMap {
+	arr := make([]Object, len(m.arr), HASHMAP_THRESHOLD)
+	copy(arr, m.arr)
+	return &TransientArrayMap{edit: &Edit{}, arr: arr}
+}
+
+func (m *TransientArrayMap) ToString(escape bool) string {
+	return transientToString(m)
+}
+
+func (m *TransientArrayMap) Equals(other interface{}) bool {
+	return m == other
+}
+
+func (m *TransientArrayMap) GetInfo() *ObjectInfo {
+	return nil
+}
+
+func (m *TransientArrayMap) WithInfo(info *ObjectInfo) Object {
+	return m
+}
+
+func (m *TransientArrayMap) GetType() *Type {
+	return TYPE.TransientArrayMap
+}
+
+func (m *TransientArrayMap) Hash() uint32 {
+	return HashPtr(uintptr(unsafe.Pointer(m)))
+}
+
+func (m *TransientArrayMap) Count() int {
+	m.edit.ensureEditable()
+	return len(m.arr) / 2
+}
+
+func (m *TransientArrayMap) indexOf(key Object) int {
+	for i := 0; i < len(m.arr); i += 2 {
+		if m.arr[i].Equals(key) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *TransientArrayMap) Get(key Object) (bool, Object) {
+	m.edit.ensureEditable()
+	if i := m.indexOf(key); i != -1 {
+		return true, m.arr[i+1]
+	}
+	return false, nil
+}
+
+// Assoc switches to a transient hash map once the array map
+// grows past HASHMAP_THRESHOLD, just like ArrayMap.Assoc does.
+func (m *TransientArrayMap) Assoc(key, val Object) Transient {
+	m.edit.ensureEditable()
+	if i := m.indexOf(key); i != -1 {
+		m.arr[i+1] = val
+		return m
+	}
+	if int64(len(m.arr)) >= HASHMAP_THRESHOLD {
+		res := &TransientHashMap{edit: m.edit}
+		for i := 0; i < len(m.arr); i += 2 {
+			res.Assoc(m.arr[i], m.arr[i+1])
+		}
+		return res.Assoc(key, val)
+	}
+	m.arr = append(m.arr, key, val)
+	return m
+}
+
+func (m *TransientArrayMap) Without(key Object) Transient {
+	m.edit.ensureEditable()
+	if i := m.indexOf(key); i != -1 {
+		copy(m.arr[i:], m.arr[i+2:])
+		m.arr = m.arr[:len(m.arr)-2]
+	}
+	return m
+}
+
+func (m *TransientArrayMap) Conj(obj Object) Transient {
+	return transientMapConj(m, obj)
+}
+
+func (m *TransientArrayMap) Persistent() Object {
+	m.edit.ensureEditable()
+	m.edit.persisted = true
+	return &ArrayMap{arr: m.arr}
+}
+
+func (m *HashMap) AsTransient() *TransientHashMap {
+	return &TransientHashMap{edit: &Edit{}, root: m.root, count: m.count}
+}
+
+func (m *TransientHashMap) ToString(escape bool) string {
+	return transientToString(m)
+}
+
+func (m *TransientHashMap) Equals(other interface{}) bool {
+	return m == other
+}
+
+func (m *TransientHashMap) GetInfo() *ObjectInfo {
+	return nil
+}
+
+func (m *TransientHashMap) WithInfo(info *ObjectInfo) Object {
+	return m
+}
+
+func (m *TransientHashMap) GetType() *Type {
+	return TYPE.TransientHashMap
+}
+
+func (m *TransientHashMap) Hash() uint32 {
+	return HashPtr(uintptr(unsafe.Pointer(m)))
+}
+
+func (m *TransientHashMap) Count() int {
+	m.edit.ensureEditable()
+	return m.count
+}
+
+func (m *TransientHashMap) Get(key Object) (bool, Object) {
+	m.edit.ensureEditable()
+	if m.root != nil {
+		if res := m.root.find(0, key.Hash(), key); res != nil {
+			return true, res.Value
+		}
+	}
+	return false, nil
+}
+
+func (m *TransientHashMap) Assoc(key, val Object) Transient {
+	m.edit.ensureEditable()
+	root := m.root
+	if root == nil {
+		root = emptyIndexedNode
+	}
+	addedLeaf := &Box{}
+	m.root = root.editAssoc(m.edit, 0, key.Hash(), key, val, addedLeaf)
+	if addedLeaf.val != nil {
+		m.count++
+	}
+	return m
+}
+
+func (m *TransientHashMap) Without(key Object) Transient {
+	m.edit.ensureEditable()
+	if m.root == nil {
+		return m
+	}
+	removedLeaf := &Box{}
+	m.root = m.root.editWithout(m.edit, 0, key.Hash(), key, removedLeaf)
+	if removedLeaf.val != nil {
+		m.count--
+	}
+	return m
+}
+
+func (m *TransientHashMap) Conj(obj Object) Transient {
+	return transientMapConj(m, obj)
+}
+
+func (m *TransientHashMap) Persistent() Object {
+	m.edit.ensureEditable()
+	m.edit.persisted = true
+	return &HashMap{count: m.count, root: m.root}
+}
+
+func (set *MapSet) AsTransient() *TransientSet {
+	return &TransientSet{m: NewTransient(set.m).(TransientMap)}
+}
+
+func (set *TransientSet) ToString(escape bool) string {
+	return transientToString(set)
+}
+
+func (set *TransientSet) Equals(other interface{}) bool {
+	return set == other
+}
+
+func (set *TransientSet) GetInfo() *ObjectInfo {
+	return nil
+}
+
+func (set *TransientSet) WithInfo(info *ObjectInfo) Object {
+	return set
+}
+
+func (set *TransientSet) GetType() *Type {
+	return TYPE.TransientSet
+}
+
+func (set *TransientSet) Hash() uint32 {
+	return HashPtr(uintptr(unsafe.Pointer(set)))
+}
+
+func (set *TransientSet) Count() int {
+	return set.m.Count()
+}
+
+func (set *TransientSet) Get(key Object) (bool, Object) {
+	if ok, _ := set.m.Get(key); ok {
+		return true, key
+	}
+	return false, nil
+}
+
+func (set *TransientSet) Conj(obj Object) Transient {
+	set.m = set.m.Assoc(obj, Boolean{B: true}).(TransientMap)
+	return set
+}
+
+func (set *TransientSet) Disjoin(key Object) Transient {
+	set.m = set.m.Without(key).(TransientMap)
+	return set
+}
+
+func (set *TransientSet) Persistent() Object {
+	return &MapSet{m: set.m.Persistent().(Map)}
+}
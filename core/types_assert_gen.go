@@ -381,6 +381,21 @@ func EnsureArgIsBigFloat(args []Object, index int) *BigFloat {
 	panic(FailArg(obj, "BigFloat", index))
 }
 
+func EnsureObjectIsBigDecimal(obj Object, pattern string) *BigDecimal {
+	if c, yes := obj.(*BigDecimal); yes {
+		return c
+	}
+	panic(FailObject(obj, "BigDecimal", pattern))
+}
+
+func EnsureArgIsBigDecimal(args []Object, index int) *BigDecimal {
+	obj := args[index]
+	if c, yes := obj.(*BigDecimal); yes {
+		return c
+	}
+	panic(FailArg(obj, "BigDecimal", index))
+}
+
 func EnsureObjectIsBigInt(obj Object, pattern string) *BigInt {
 	if c, yes := obj.(*BigInt); yes {
 		return c
@@ -650,3 +665,168 @@ func EnsureArgIsCountedIndexed(args []Object, index int) CountedIndexed {
 	}
 	panic(FailArg(obj, "CountedIndexed", index))
 }
+
+func EnsureObjectIsTransient(obj Object, pattern string) Transient {
+	if c, yes := obj.(Transient); yes {
+		return c
+	}
+	panic(FailObject(obj, "Transient", pattern))
+}
+
+func EnsureArgIsTransient(args []Object, index int) Transient {
+	obj := args[index]
+	if c, yes := obj.(Transient); yes {
+		return c
+	}
+	panic(FailArg(obj, "Transient", index))
+}
+
+func EnsureObjectIsSorted(obj Object, pattern string) Sorted {
+	if c, yes := obj.(Sorted); yes {
+		return c
+	}
+	panic(FailObject(obj, "Sorted", pattern))
+}
+
+func EnsureArgIsSorted(args []Object, index int) Sorted {
+	obj := args[index]
+	if c, yes := obj.(Sorted); yes {
+		return c
+	}
+	panic(FailArg(obj, "Sorted", index))
+}
+
+func EnsureObjectIsVolatile(obj Object, pattern string) *Volatile {
+	if c, yes := obj.(*Volatile); yes {
+		return c
+	}
+	panic(FailObject(obj, "Volatile", pattern))
+}
+
+func EnsureArgIsVolatile(args []Object, index int) *Volatile {
+	obj := args[index]
+	if c, yes := obj.(*Volatile); yes {
+		return c
+	}
+	panic(FailArg(obj, "Volatile", index))
+}
+
+func EnsureObjectIsBlockingDeref(obj Object, pattern string) BlockingDeref {
+	if c, yes := obj.(BlockingDeref); yes {
+		return c
+	}
+	panic(FailObject(obj, "BlockingDeref", pattern))
+}
+
+func EnsureArgIsBlockingDeref(args []Object, index int) BlockingDeref {
+	obj := args[index]
+	if c, yes := obj.(BlockingDeref); yes {
+		return c
+	}
+	panic(FailArg(obj, "BlockingDeref", index))
+}
+
+func EnsureObjectIsFuture(obj Object, pattern string) *Future {
+	if c, yes := obj.(*Future); yes {
+		return c
+	}
+	panic(FailObject(obj, "Future", pattern))
+}
+
+func EnsureArgIsFuture(args []Object, index int) *Future {
+	obj := args[index]
+	if c, yes := obj.(*Future); yes {
+		return c
+	}
+	panic(FailArg(obj, "Future", index))
+}
+
+func EnsureObjectIsPromise(obj Object, pattern string) *Promise {
+	if c, yes := obj.(*Promise); yes {
+		return c
+	}
+	panic(FailObject(obj, "Promise", pattern))
+}
+
+func EnsureArgIsPromise(args []Object, index int) *Promise {
+	obj := args[index]
+	if c, yes := obj.(*Promise); yes {
+		return c
+	}
+	panic(FailArg(obj, "Promise", index))
+}
+
+func EnsureObjectIsChannelBuffer(obj Object, pattern string) *ChannelBuffer {
+	if c, yes := obj.(*ChannelBuffer); yes {
+		return c
+	}
+	panic(FailObject(obj, "ChannelBuffer", pattern))
+}
+
+func EnsureArgIsChannelBuffer(args []Object, index int) *ChannelBuffer {
+	obj := args[index]
+	if c, yes := obj.(*ChannelBuffer); yes {
+		return c
+	}
+	panic(FailArg(obj, "ChannelBuffer", index))
+}
+
+func EnsureObjectIsAgent(obj Object, pattern string) *Agent {
+	if c, yes := obj.(*Agent); yes {
+		return c
+	}
+	panic(FailObject(obj, "Agent", pattern))
+}
+
+func EnsureArgIsAgent(args []Object, index int) *Agent {
+	obj := args[index]
+	if c, yes := obj.(*Agent); yes {
+		return c
+	}
+	panic(FailArg(obj, "Agent", index))
+}
+
+func EnsureObjectIsWatchable(obj Object, pattern string) Watchable {
+	if c, yes := obj.(Watchable); yes {
+		return c
+	}
+	panic(FailObject(obj, "Watchable", pattern))
+}
+
+func EnsureArgIsWatchable(args []Object, index int) Watchable {
+	obj := args[index]
+	if c, yes := obj.(Watchable); yes {
+		return c
+	}
+	panic(FailArg(obj, "Watchable", index))
+}
+
+func EnsureObjectIsBytes(obj Object, pattern string) *Bytes {
+	if c, yes := obj.(*Bytes); yes {
+		return c
+	}
+	panic(FailObject(obj, "Bytes", pattern))
+}
+
+func EnsureArgIsBytes(args []Object, index int) *Bytes {
+	obj := args[index]
+	if c, yes := obj.(*Bytes); yes {
+		return c
+	}
+	panic(FailArg(obj, "Bytes", index))
+}
+
+func EnsureObjectIsUUID(obj Object, pattern string) UUID {
+	if c, yes := obj.(UUID); yes {
+		return c
+	}
+	panic(FailObject(obj, "UUID", pattern))
+}
+
+func EnsureArgIsUUID(args []Object, index int) UUID {
+	obj := args[index]
+	if c, yes := obj.(UUID); yes {
+		return c
+	}
+	panic(FailArg(obj, "UUID", index))
+}
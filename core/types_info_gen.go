@@ -57,6 +57,11 @@ func (x *BigFloat) WithInfo(info *ObjectInfo) Object {
 	return x
 }
 
+func (x *BigDecimal) WithInfo(info *ObjectInfo) Object {
+	x.info = info
+	return x
+}
+
 func (x Char) WithInfo(info *ObjectInfo) Object {
 	x.info = info
 	return x
@@ -161,3 +166,38 @@ func (x *VectorRSeq) WithInfo(info *ObjectInfo) Object {
 	x.info = info
 	return x
 }
+
+func (x *Record) WithInfo(info *ObjectInfo) Object {
+	x.info = info
+	return x
+}
+
+func (x *SortedMap) WithInfo(info *ObjectInfo) Object {
+	x.info = info
+	return x
+}
+
+func (x *SortedMapSeq) WithInfo(info *ObjectInfo) Object {
+	x.info = info
+	return x
+}
+
+func (x *SortedSet) WithInfo(info *ObjectInfo) Object {
+	x.info = info
+	return x
+}
+
+func (x *Bytes) WithInfo(info *ObjectInfo) Object {
+	x.info = info
+	return x
+}
+
+func (x UUID) WithInfo(info *ObjectInfo) Object {
+	x.info = info
+	return x
+}
+
+func (x *TaggedLiteral) WithInfo(info *ObjectInfo) Object {
+	x.info = info
+	return x
+}
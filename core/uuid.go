@@ -0,0 +1,105 @@
+package core
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+)
+
+type (
+	// UUID is a 128-bit universally unique identifier, read and printed
+	// as #uuid "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx".
+	UUID struct {
+		InfoHolder
+		u [16]byte
+	}
+)
+
+var errInvalidUUID = errors.New("Invalid UUID string")
+
+func MakeUUID(u [16]byte) UUID {
+	return UUID{u: u}
+}
+
+// RandomUUID returns a new random (version 4) UUID.
+func RandomUUID() UUID {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		panic(RT.NewError("Error generating UUID: " + err.Error()))
+	}
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+	return MakeUUID(u)
+}
+
+// ParseUUID parses the canonical 8-4-4-4-12 hexadecimal form of a UUID.
+func ParseUUID(s string) (UUID, error) {
+	var res UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return res, errInvalidUUID
+	}
+	dst := res.u[:]
+	for _, part := range []string{s[0:8], s[9:13], s[14:18], s[19:23], s[24:36]} {
+		n, err := hex.Decode(dst, []byte(part))
+		if err != nil {
+			return res, errInvalidUUID
+		}
+		dst = dst[n:]
+	}
+	return res, nil
+}
+
+// Bytes returns the 16 bytes of the UUID.
+func (u UUID) Bytes() [16]byte {
+	return u.u
+}
+
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[:], u.u[:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u.u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u.u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u.u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u.u[10:])
+	return string(buf[:])
+}
+
+func (u UUID) ToString(escape bool) string {
+	if escape {
+		return "#uuid \"" + u.String() + "\""
+	}
+	return u.String()
+}
+
+func (u UUID) Equals(other interface{}) bool {
+	switch other := other.(type) {
+	case UUID:
+		return u.u == other.u
+	default:
+		return false
+	}
+}
+
+func (u UUID) GetType() *Type {
+	return TYPE.UUID
+}
+
+func (u UUID) Native() interface{} {
+	return u.String()
+}
+
+func (u UUID) Hash() uint32 {
+	h := getHash()
+	h.Write(u.u[:])
+	return h.Sum32()
+}
+
+func (u UUID) Compare(other Object) int {
+	u2 := EnsureObjectIsUUID(other, "Cannot compare UUID: %s")
+	return bytes.Compare(u.u[:], u2.u[:])
+}
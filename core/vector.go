@@ -337,7 +337,13 @@ func (v *Vector) popTail(level uint, node []interface{}) []interface{} {
 			return nil
 		} else {
 			ret := clone(node)
-			ret[subidx] = newChild
+			if newChild == nil {
+				// Avoid storing a typed nil, which pushTail
+				// would mistake for an existing child.
+				ret[subidx] = nil
+			} else {
+				ret[subidx] = newChild
+			}
 			return ret
 		}
 	} else if subidx == 0 {
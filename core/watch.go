@@ -0,0 +1,75 @@
+package core
+
+type (
+	// Watchable is implemented by reference types that support
+	// watches and validators: atoms, vars and agents.
+	Watchable interface {
+		Deref
+		AddWatch(key Object, fn Callable)
+		RemoveWatch(key Object)
+		Validator() Callable
+		SetValidator(fn Callable)
+	}
+	WatchHolder struct {
+		watches   Map
+		validator Callable
+	}
+)
+
+func (w *WatchHolder) AddWatch(key Object, fn Callable) {
+	if w.watches == nil {
+		w.watches = EmptyArrayMap()
+	}
+	w.watches = w.watches.Assoc(key, fn.(Object)).(Map)
+}
+
+func (w *WatchHolder) RemoveWatch(key Object) {
+	if w.watches != nil {
+		w.watches = w.watches.Without(key)
+	}
+}
+
+func (w *WatchHolder) Validator() Callable {
+	return w.validator
+}
+
+func (w *WatchHolder) SetValidator(fn Callable) {
+	w.validator = fn
+}
+
+// validate panics if the validator rejects value.
+func (w *WatchHolder) validate(value Object) {
+	if w.validator != nil {
+		validateRefState(w.validator, value)
+	}
+}
+
+func validateRefState(validator Callable, value Object) {
+	if !ToBool(validator.Call([]Object{value})) {
+		panic(RT.NewError("Invalid reference state: " + value.ToString(true)))
+	}
+}
+
+// notifyWatches calls every watch fn with the key, the reference,
+// and its old and new values.
+func (w *WatchHolder) notifyWatches(ref Object, oldValue, newValue Object) {
+	if w.watches == nil || w.watches.Count() == 0 {
+		return
+	}
+	// Watches may add or remove watches, so iterate over a snapshot.
+	for iter := w.watches.Iter(); iter.HasNext(); {
+		p := iter.Next()
+		p.Value.(Callable).Call([]Object{p.Key, ref, oldValue, newValue})
+	}
+}
+
+// setValue validates newValue, stores it in *place and notifies the watches.
+func (w *WatchHolder) setValue(ref Object, place *Object, newValue Object) {
+	w.validate(newValue)
+	oldValue := *place
+	*place = newValue
+	if oldValue == nil {
+		oldValue = NIL
+	}
+	w.notifyWatches(ref, oldValue, newValue)
+}
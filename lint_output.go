@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	. "github.com/candid82/joker/core"
+)
+
+// Machine-readable linter output (--lint-output json|sarif|edn). Each
+// problem is reported with its file, start and end position (lines and
+// columns are 1-based, the end column being that of the last
+// character), severity, code (e.g. "unused-binding") and message.
+
+var lintOutputFormats = map[string]func(io.Writer, []Problem){
+	"json":  writeLintJSON,
+	"sarif": writeLintSARIF,
+	"edn":   writeLintEDN,
+}
+
+type lintRecord struct {
+	File        string `json:"file"`
+	StartLine   int    `json:"startLine"`
+	StartColumn int    `json:"startColumn"`
+	EndLine     int    `json:"endLine"`
+	EndColumn   int    `json:"endColumn"`
+	Severity    string `json:"severity"`
+	Code        string `json:"code"`
+	Message     string `json:"message"`
+}
+
+func makeLintRecord(p Problem) lintRecord {
+	startLine, startColumn := p.Start()
+	endLine, endColumn := p.End()
+	// Read errors at the end of file have column 0, but consumers
+	// (SARIF in particular) expect 1-based lines and columns.
+	startLine, startColumn = max(startLine, 1), max(startColumn, 1)
+	endLine = max(endLine, startLine)
+	if endLine == startLine {
+		endColumn = max(endColumn, startColumn)
+	}
+	return lintRecord{
+		File:        p.Filename(),
+		StartLine:   startLine,
+		StartColumn: startColumn,
+		EndLine:     endLine,
+		EndColumn:   endColumn,
+		Severity:    p.Severity(),
+		Code:        p.Code,
+		// Some messages end with a newline meant for the text output.
+		Message: strings.TrimSpace(p.Message),
+	}
+}
+
+func writeLintJSON(w io.Writer, problems []Problem) {
+	records := make([]lintRecord, len(problems))
+	for i, p := range problems {
+		records[i] = makeLintRecord(p)
+	}
+	b, _ := json.MarshalIndent(records, "", "  ")
+	fmt.Fprintln(w, string(b))
+}
+
+func writeLintEDN(w io.Writer, problems []Problem) {
+	fmt.Fprint(w, "[")
+	for i, p := range problems {
+		r := makeLintRecord(p)
+		m := EmptyArrayMap()
+		m.Add(MakeKeyword("file"), MakeString(r.File))
+		m.Add(MakeKeyword("start-line"), MakeInt(r.StartLine))
+		m.Add(MakeKeyword("start-column"), MakeInt(r.StartColumn))
+		m.Add(MakeKeyword("end-line"), MakeInt(r.EndLine))
+		m.Add(MakeKeyword("end-column"), MakeInt(r.EndColumn))
+		m.Add(MakeKeyword("severity"), MakeKeyword(r.Severity))
+		m.Add(MakeKeyword("code"), MakeKeyword(r.Code))
+		m.Add(MakeKeyword("message"), MakeString(r.Message))
+		if i > 0 {
+			fmt.Fprint(w, "\n ")
+		}
+		fmt.Fprint(w, m.ToString(true))
+	}
+	fmt.Fprintln(w, "]")
+}
+
+// writeLintSARIF writes a SARIF 2.1.0 log, as consumed by GitHub code
+// scanning and other tools. SARIF end columns are exclusive.
+func writeLintSARIF(w io.Writer, problems []Problem) {
+	type obj = map[string]interface{}
+	codes := map[string]bool{}
+	results := []obj{}
+	for _, p := range problems {
+		r := makeLintRecord(p)
+		codes[r.Code] = true
+		results = append(results, obj{
+			"ruleId":  r.Code,
+			"level":   r.Severity,
+			"message": obj{"text": r.Message},
+			"locations": []obj{{
+				"physicalLocation": obj{
+					"artifactLocation": obj{"uri": r.File},
+					"region": obj{
+						"startLine":   r.StartLine,
+						"startColumn": r.StartColumn,
+						"endLine":     r.EndLine,
+						"endColumn":   r.EndColumn + 1,
+					},
+				},
+			}},
+		})
+	}
+	rules := []obj{}
+	for code := range codes {
+		rules = append(rules, obj{"id": code})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i]["id"].(string) < rules[j]["id"].(string)
+	})
+	log := obj{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": []obj{{
+			"tool": obj{
+				"driver": obj{
+					"name":           "joker",
+					"version":        VERSION[1:],
+					"informationUri": "https://github.com/candid82/joker",
+					"rules":          rules,
+				},
+			},
+			"results": results,
+		}},
+	}
+	b, _ := json.MarshalIndent(log, "", "  ")
+	fmt.Fprintln(w, string(b))
+}
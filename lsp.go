@@ -0,0 +1,582 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	. "github.com/candid82/joker/core"
+)
+
+// Language Server Protocol (https://microsoft.github.io/language-server-protocol/)
+// server over stdio, built on the linter: open documents are linted
+// (in memory) on every change, and the linter's warnings are published
+// as diagnostics. The vars and namespaces the linter defines along the
+// way are used for go-to-definition, hover, document symbols and
+// completion.
+//
+// Like `joker --lint --working-dir`, the server lints one dialect, given
+// with --dialect or detected from the first document opened.
+
+type (
+	lspServer struct {
+		in         *bufio.Reader
+		out        io.Writer
+		dialect    Dialect
+		workingDir string
+		configured bool
+		shutdown   bool
+		docs       map[string]*lspDocument
+	}
+
+	lspDocument struct {
+		path  string
+		text  string
+		lines []string
+		ns    *Namespace // the current namespace at the end of the document
+	}
+
+	lspMessage struct {
+		JSONRPC string           `json:"jsonrpc"`
+		ID      *json.RawMessage `json:"id,omitempty"`
+		Method  string           `json:"method,omitempty"`
+		Params  json.RawMessage  `json:"params,omitempty"`
+		Result  interface{}      `json:"result,omitempty"`
+		Error   *lspError        `json:"error,omitempty"`
+	}
+
+	lspError struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+
+	lspPosition struct {
+		Line      int `json:"line"`
+		Character int `json:"character"`
+	}
+
+	lspRange struct {
+		Start lspPosition `json:"start"`
+		End   lspPosition `json:"end"`
+	}
+
+	lspLocation struct {
+		URI   string   `json:"uri"`
+		Range lspRange `json:"range"`
+	}
+
+	lspDiagnostic struct {
+		Range    lspRange `json:"range"`
+		Severity int      `json:"severity"`
+		Code     string   `json:"code,omitempty"`
+		Source   string   `json:"source"`
+		Message  string   `json:"message"`
+	}
+
+	lspTextDocumentPosition struct {
+		TextDocument struct {
+			URI string `json:"uri"`
+		} `json:"textDocument"`
+		Position lspPosition `json:"position"`
+	}
+)
+
+const (
+	lspSeverityError   = 1
+	lspSeverityWarning = 2
+	// Messages are read into memory whole, so their size is limited.
+	lspMaxContentLength = 64 << 20
+)
+
+func lsp(dialect Dialect, workingDir string) {
+	s := &lspServer{
+		in:         bufio.NewReader(Stdin),
+		out:        Stdout,
+		dialect:    dialect,
+		workingDir: workingDir,
+		docs:       make(map[string]*lspDocument),
+	}
+	for {
+		msg, err := s.read()
+		if err != nil {
+			if err != io.EOF {
+				fmt.Fprintln(Stderr, "LSP:", err)
+			}
+			ExitJoker(1)
+		}
+		if msg.Method == "exit" {
+			if s.shutdown {
+				ExitJoker(0)
+			}
+			ExitJoker(1)
+		}
+		s.handle(msg)
+	}
+}
+
+func (s *lspServer) read() (*lspMessage, error) {
+	header, err := textproto.NewReader(s.in).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || length < 0 || length > lspMaxContentLength {
+		return nil, fmt.Errorf("invalid Content-Length: %q", header.Get("Content-Length"))
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(s.in, body); err != nil {
+		return nil, err
+	}
+	msg := &lspMessage{}
+	if err := json.Unmarshal(body, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (s *lspServer) write(msg *lspMessage) {
+	msg.JSONRPC = "2.0"
+	body, err := json.Marshal(msg)
+	if err != nil {
+		fmt.Fprintln(Stderr, "LSP:", err)
+		return
+	}
+	fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+func (s *lspServer) notify(method string, params interface{}) {
+	raw, _ := json.Marshal(params)
+	s.write(&lspMessage{Method: method, Params: raw})
+}
+
+func (s *lspServer) handle(msg *lspMessage) {
+	var result interface{}
+	switch msg.Method {
+	case "initialize":
+		var params struct {
+			RootURI string `json:"rootUri"`
+		}
+		json.Unmarshal(msg.Params, &params)
+		if s.workingDir == "" && params.RootURI != "" {
+			s.workingDir = uriToPath(params.RootURI)
+		}
+		result = map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync":       1, // full
+				"definitionProvider":     true,
+				"hoverProvider":          true,
+				"documentSymbolProvider": true,
+				"completionProvider":     map[string]interface{}{"triggerCharacters": []string{"/"}},
+			},
+			"serverInfo": map[string]interface{}{"name": "joker", "version": VERSION},
+		}
+	case "shutdown":
+		s.shutdown = true
+	case "textDocument/didOpen":
+		var params struct {
+			TextDocument struct {
+				URI  string `json:"uri"`
+				Text string `json:"text"`
+			} `json:"textDocument"`
+		}
+		json.Unmarshal(msg.Params, &params)
+		s.update(params.TextDocument.URI, params.TextDocument.Text)
+	case "textDocument/didChange":
+		var params struct {
+			TextDocument struct {
+				URI string `json:"uri"`
+			} `json:"textDocument"`
+			ContentChanges []struct {
+				Text string `json:"text"`
+			} `json:"contentChanges"`
+		}
+		json.Unmarshal(msg.Params, &params)
+		if n := len(params.ContentChanges); n > 0 {
+			s.update(params.TextDocument.URI, params.ContentChanges[n-1].Text)
+		}
+	case "textDocument/didClose":
+		var params struct {
+			TextDocument struct {
+				URI string `json:"uri"`
+			} `json:"textDocument"`
+		}
+		json.Unmarshal(msg.Params, &params)
+		delete(s.docs, params.TextDocument.URI)
+		s.notify("textDocument/publishDiagnostics", map[string]interface{}{"uri": params.TextDocument.URI, "diagnostics": []lspDiagnostic{}})
+	case "textDocument/definition":
+		if vr, _ := s.varAt(msg.Params); vr != nil {
+			if loc, ok := varLocation(vr); ok {
+				result = loc
+			}
+		}
+	case "textDocument/hover":
+		if vr, r := s.varAt(msg.Params); vr != nil {
+			result = map[string]interface{}{
+				"contents": map[string]interface{}{"kind": "markdown", "value": s.hoverText(vr)},
+				"range":    r,
+			}
+		}
+	case "textDocument/documentSymbol":
+		var params struct {
+			TextDocument struct {
+				URI string `json:"uri"`
+			} `json:"textDocument"`
+		}
+		json.Unmarshal(msg.Params, &params)
+		if doc := s.docs[params.TextDocument.URI]; doc != nil {
+			result = s.documentSymbols(doc, params.TextDocument.URI)
+		}
+	case "textDocument/completion":
+		result = s.completions(msg.Params)
+	default:
+		if msg.ID != nil && !strings.HasPrefix(msg.Method, "$/") {
+			s.write(&lspMessage{ID: msg.ID, Error: &lspError{Code: -32601, Message: "Method not found: " + msg.Method}})
+		}
+		return
+	}
+	if msg.ID != nil {
+		if result == nil {
+			// Responses must have a result, even if it's null.
+			result = json.RawMessage("null")
+		}
+		s.write(&lspMessage{ID: msg.ID, Result: result})
+	}
+}
+
+// configure puts Joker into linter mode for the server's dialect
+// (detected from path unless given) the first time a document is linted.
+func (s *lspServer) configure(path string) {
+	if s.configured {
+		return
+	}
+	s.configured = true
+	if s.dialect == UNKNOWN {
+		s.dialect = detectDialect(path)
+	}
+	filename := ""
+	if s.workingDir == "" {
+		filename = path
+	}
+	ReadConfig(filename, s.workingDir)
+	configureLinterMode(s.dialect, filename, s.workingDir)
+}
+
+// update lints the document's new text and publishes the warnings as
+// diagnostics.
+func (s *lspServer) update(uri string, text string) {
+	path := uriToPath(uri)
+	s.configure(path)
+	doc := s.docs[uri]
+	if doc == nil {
+		doc = &lspDocument{path: path}
+		s.docs[uri] = doc
+	}
+	doc.text = text
+	doc.lines = strings.Split(text, "\n")
+	s.notify("textDocument/publishDiagnostics", map[string]interface{}{"uri": uri, "diagnostics": s.lint(doc)})
+}
+
+func (s *lspServer) lint(doc *lspDocument) []lspDiagnostic {
+	// Forget what linting the previous version of the document defined.
+	if doc.ns != nil && doc.ns != GLOBAL_ENV.CoreNamespace && doc.ns.Name.Name() != "user" {
+		delete(GLOBAL_ENV.Namespaces, STRINGS.Intern(doc.ns.Name.Name()))
+	}
+	phase := PARSE
+	if s.dialect == EDN {
+		phase = READ
+	}
+	oldStdout, oldStderr := Stdout, Stderr
+	Stdout, Stderr = io.Discard, io.Discard
+	COLLECT_PROBLEMS, PROBLEMS = true, nil
+	ns := GLOBAL_ENV.CurrentNamespace()
+	GLOBAL_ENV.CoreNamespace.Resolve("*loaded-libs*").Value = EmptySet()
+	if ProcessReader(NewReader(strings.NewReader(doc.text), doc.path), doc.path, phase) == nil {
+		WarnOnUnusedNamespaces()
+		WarnOnUnusedVars()
+	}
+	ResetUsage()
+	doc.ns = GLOBAL_ENV.CurrentNamespace()
+	GLOBAL_ENV.SetCurrentNamespace(ns)
+	Stdout, Stderr = oldStdout, oldStderr
+	COLLECT_PROBLEMS = false
+
+	diagnostics := []lspDiagnostic{}
+	for _, p := range PROBLEMS {
+		if p.Filename() != doc.path {
+			continue
+		}
+		severity := lspSeverityError
+		if p.Severity() == "warning" {
+			severity = lspSeverityWarning
+		}
+		start := doc.position(p.Start())
+		diagnostics = append(diagnostics, lspDiagnostic{
+			Range:    lspRange{Start: start, End: doc.symbolEnd(start)},
+			Severity: severity,
+			Code:     p.Code,
+			Source:   "joker",
+			Message:  p.Kind + ": " + p.Message,
+		})
+	}
+	return diagnostics
+}
+
+// varAt returns the var named by the symbol at the position in the
+// textDocument/definition or hover request params, and the symbol's range.
+func (s *lspServer) varAt(params json.RawMessage) (*Var, lspRange) {
+	var p lspTextDocumentPosition
+	json.Unmarshal(params, &p)
+	doc := s.docs[p.TextDocument.URI]
+	if doc == nil || doc.ns == nil {
+		return nil, lspRange{}
+	}
+	r := doc.symbolAt(p.Position)
+	name := doc.textIn(r)
+	if name == "" {
+		return nil, r
+	}
+	vr, _ := GLOBAL_ENV.ResolveIn(doc.ns, MakeSymbol(name))
+	return vr, r
+}
+
+func (s *lspServer) completions(params json.RawMessage) []map[string]interface{} {
+	res := []map[string]interface{}{}
+	var p lspTextDocumentPosition
+	json.Unmarshal(params, &p)
+	doc := s.docs[p.TextDocument.URI]
+	if doc == nil || doc.ns == nil {
+		return res
+	}
+	r := doc.symbolAt(p.Position)
+	r.End = p.Position
+	for _, c := range nreplCompletions(doc.ns, doc.textIn(r)) {
+		c := c.(map[string]interface{})
+		kind := 6 // Variable
+		switch c["type"] {
+		case "function":
+			kind = 3
+		case "macro":
+			kind = 14 // Keyword
+		case "namespace":
+			kind = 9 // Module
+		}
+		res = append(res, map[string]interface{}{"label": c["candidate"], "kind": kind})
+	}
+	return res
+}
+
+func varLocation(vr *Var) (lspLocation, bool) {
+	info := vr.GetInfo()
+	if info == nil || !filepath.IsAbs(info.Filename()) {
+		return lspLocation{}, false
+	}
+	startLine, startColumn := info.Start()
+	endLine, endColumn := info.End()
+	return lspLocation{
+		URI: pathToURI(info.Filename()),
+		Range: lspRange{
+			Start: lspPosition{Line: startLine - 1, Character: startColumn - 1},
+			End:   lspPosition{Line: endLine - 1, Character: endColumn},
+		},
+	}, true
+}
+
+// hoverText describes vr in markdown. Vars defined in linted code don't
+// have metadata (as the code isn't evaluated), so their arglists and
+// docstrings are taken from their definition if it's in an open document.
+func (s *lspServer) hoverText(vr *Var) string {
+	info := nreplVarInfo(vr)
+	arglists, _ := info["arglists-str"].(string)
+	doc, _ := info["doc"].(string)
+	if arglists == "" && doc == "" {
+		arglists, doc = s.definitionInfo(vr)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "```clojure\n%s\n```\n", vr.Name())
+	if arglists != "" {
+		fmt.Fprintf(&b, "```clojure\n%s\n```\n", arglists)
+	}
+	if doc != "" {
+		fmt.Fprintf(&b, "\n%s\n", doc)
+	}
+	return b.String()
+}
+
+// definitionInfo returns the arglists (one per line) and the docstring
+// in vr's definition, e.g. (defn name "doc" [args] body).
+func (s *lspServer) definitionInfo(vr *Var) (arglists string, docstring string) {
+	loc, ok := varLocation(vr)
+	if !ok {
+		return "", ""
+	}
+	doc := s.docs[loc.URI]
+	if doc == nil || loc.Range.Start.Line >= len(doc.lines) {
+		return "", ""
+	}
+	text := strings.Join(doc.lines[loc.Range.Start.Line:], "\n")
+	runes := []rune(doc.lines[loc.Range.Start.Line])
+	text = text[len(string(runes[:runeIndex(runes, loc.Range.Start.Character)])):]
+	obj, err := TryRead(NewReader(strings.NewReader(text), doc.path))
+	seq, ok := obj.(Seq)
+	if err != nil || !ok {
+		return "", ""
+	}
+	form := ToSlice(seq)
+	if len(form) < 3 {
+		return "", ""
+	}
+	head, _ := form[0].(Symbol)
+	rest := form[2:]
+	if str, ok := rest[0].(String); ok && (head.Name() != "def" || len(rest) == 2) {
+		docstring = str.S
+		rest = rest[1:]
+	}
+	if head.Name() == "def" {
+		return "", docstring
+	}
+	if len(rest) > 0 {
+		if _, ok := rest[0].(Map); ok {
+			rest = rest[1:]
+		}
+	}
+	var lines []string
+	for _, obj := range rest {
+		if vec, ok := obj.(Vec); ok {
+			lines = append(lines, vec.ToString(true))
+			break
+		}
+		if arity, ok := obj.(Seq); ok {
+			if vec, ok := arity.First().(Vec); ok {
+				lines = append(lines, vec.ToString(true))
+			}
+		}
+	}
+	return strings.Join(lines, "\n"), docstring
+}
+
+// documentSymbols returns the vars defined in doc's namespace by doc.
+func (s *lspServer) documentSymbols(doc *lspDocument, uri string) []map[string]interface{} {
+	res := []map[string]interface{}{}
+	if doc.ns == nil {
+		return res
+	}
+	nsName := doc.ns.Name.Name()
+	for _, vr := range doc.ns.Mappings() {
+		loc, ok := varLocation(vr)
+		if !ok || loc.URI != uri || vr.Namespace() != doc.ns {
+			continue
+		}
+		kind := 13 // Variable
+		if arglists, _ := s.definitionInfo(vr); arglists != "" || nreplVarType(vr) != "var" {
+			kind = 12 // Function
+		}
+		res = append(res, map[string]interface{}{
+			"name":          vr.Symbol().Name(),
+			"kind":          kind,
+			"location":      loc,
+			"containerName": nsName,
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		a, b := res[i]["location"].(lspLocation).Range.Start, res[j]["location"].(lspLocation).Range.Start
+		return a.Line < b.Line || a.Line == b.Line && a.Character < b.Character
+	})
+	return res
+}
+
+func uriToPath(uri string) string {
+	if u, err := url.Parse(uri); err == nil && u.Scheme == "file" {
+		return u.Path
+	}
+	return uri
+}
+
+func pathToURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
+
+// position converts a (1-based) line and column in doc to an LSP position,
+// clamped to the document (e.g. read errors at EOF have column 0).
+func (doc *lspDocument) position(line, column int) lspPosition {
+	if line < 1 {
+		line, column = 1, 1
+	}
+	if line > len(doc.lines) {
+		line, column = len(doc.lines), len([]rune(doc.lines[len(doc.lines)-1]))+1
+	}
+	runes := []rune(doc.lines[line-1])
+	if column < 1 {
+		column = 1
+	}
+	if column-1 > len(runes) {
+		column = len(runes) + 1
+	}
+	return lspPosition{Line: line - 1, Character: len(utf16.Encode(runes[:column-1]))}
+}
+
+func isSymbolRune(r rune) bool {
+	return !strings.ContainsRune(" \t\r\n,()[]{}\"';@^`~\\", r)
+}
+
+// runeIndex converts the position's character (in UTF-16 code units)
+// to an index in runes.
+func runeIndex(runes []rune, character int) int {
+	for i, units := 0, 0; i < len(runes); i++ {
+		if units >= character {
+			return i
+		}
+		units += utf16.RuneLen(runes[i])
+	}
+	return len(runes)
+}
+
+// symbolAt returns the range of the symbol at (or just before) pos.
+func (doc *lspDocument) symbolAt(pos lspPosition) lspRange {
+	if pos.Line < 0 || pos.Line >= len(doc.lines) {
+		return lspRange{Start: pos, End: pos}
+	}
+	runes := []rune(doc.lines[pos.Line])
+	start := runeIndex(runes, pos.Character)
+	end := start
+	for start > 0 && isSymbolRune(runes[start-1]) {
+		start--
+	}
+	for end < len(runes) && isSymbolRune(runes[end]) {
+		end++
+	}
+	return lspRange{
+		Start: lspPosition{Line: pos.Line, Character: len(utf16.Encode(runes[:start]))},
+		End:   lspPosition{Line: pos.Line, Character: len(utf16.Encode(runes[:end]))},
+	}
+}
+
+// symbolEnd returns the end of the symbol starting at start, or the
+// position after start if there's no symbol there.
+func (doc *lspDocument) symbolEnd(start lspPosition) lspPosition {
+	end := doc.symbolAt(lspPosition{Line: start.Line, Character: start.Character + 1}).End
+	if end.Line != start.Line || end.Character <= start.Character {
+		return lspPosition{Line: start.Line, Character: start.Character + 1}
+	}
+	return end
+}
+
+// textIn returns the text in a range on a single line.
+func (doc *lspDocument) textIn(r lspRange) string {
+	if r.Start.Line < 0 || r.Start.Line >= len(doc.lines) {
+		return ""
+	}
+	runes := []rune(doc.lines[r.Start.Line])
+	start, end := runeIndex(runes, r.Start.Character), runeIndex(runes, r.End.Character)
+	if start >= end {
+		return ""
+	}
+	return string(runes[start:end])
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"os"
+	"os/exec"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type lspClient struct {
+	t      *testing.T
+	cmd    *exec.Cmd
+	in     io.WriteCloser
+	out    *bufio.Reader
+	lastID int
+}
+
+// startLsp runs the language server in a new process of the test
+// binary (see TestMain).
+func startLsp(t *testing.T) *lspClient {
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	cmd.Env = append(os.Environ(), "JOKER_TEST_LSP=1")
+	cmd.Stderr = os.Stderr
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cmd.Process.Kill() })
+	return &lspClient{t: t, cmd: cmd, in: in, out: bufio.NewReader(out)}
+}
+
+func (c *lspClient) send(msg map[string]interface{}) {
+	c.t.Helper()
+	msg["jsonrpc"] = "2.0"
+	body, _ := json.Marshal(msg)
+	if _, err := fmt.Fprintf(c.in, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// receive returns the next message, decoded into a generic map.
+func (c *lspClient) receive() map[string]interface{} {
+	c.t.Helper()
+	header, err := textproto.NewReader(c.out).ReadMIMEHeader()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	length, _ := strconv.Atoi(header.Get("Content-Length"))
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.out, body); err != nil {
+		c.t.Fatal(err)
+	}
+	var msg map[string]interface{}
+	if err := json.Unmarshal(body, &msg); err != nil {
+		c.t.Fatal(err)
+	}
+	return msg
+}
+
+func (c *lspClient) request(method string, params interface{}) interface{} {
+	c.t.Helper()
+	c.lastID++
+	c.send(map[string]interface{}{"id": c.lastID, "method": method, "params": params})
+	res := c.receive()
+	if res["id"] != float64(c.lastID) {
+		c.t.Fatalf("expected response to %s, got %v", method, res)
+	}
+	return res["result"]
+}
+
+// toJSON converts v to what decoding its JSON gives, for comparisons.
+func toJSON(v interface{}) interface{} {
+	b, _ := json.Marshal(v)
+	var res interface{}
+	json.Unmarshal(b, &res)
+	return res
+}
+
+func lspRangeAt(line, start, end int) lspRange {
+	return lspRange{Start: lspPosition{Line: line, Character: start}, End: lspPosition{Line: line, Character: end}}
+}
+
+const lspTestText = `(ns lsp.test
+  (:require [clojure.string :as str]))
+
+(defn greet
+  "Greets who."
+  [who]
+  (str/join ", " ["Hello" who]))
+
+(def ^:private unused 1)
+(greet)
+(undefined)
+`
+
+func TestLsp(t *testing.T) {
+	c := startLsp(t)
+	uri := "file:///tmp/lsp-test/src/test.clj"
+	at := func(line, character int) map[string]interface{} {
+		return map[string]interface{}{"textDocument": map[string]interface{}{"uri": uri}, "position": lspPosition{Line: line, Character: character}}
+	}
+
+	res := c.request("initialize", map[string]interface{}{"rootUri": "file:///tmp/lsp-test"})
+	if caps, ok := res.(map[string]interface{})["capabilities"].(map[string]interface{}); !ok || caps["hoverProvider"] != true {
+		t.Errorf("unexpected initialize result %v", res)
+	}
+	c.send(map[string]interface{}{"method": "initialized", "params": map[string]interface{}{}})
+
+	c.send(map[string]interface{}{"method": "textDocument/didOpen", "params": map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "clojure", "version": 1, "text": lspTestText}}})
+	msg := c.receive()
+	expected := toJSON(map[string]interface{}{"uri": uri, "diagnostics": []lspDiagnostic{
+		{Range: lspRangeAt(9, 0, 6), Severity: lspSeverityWarning, Code: "wrong-arity", Source: "joker", Message: "Parse warning: Wrong number of args (0) passed to lsp.test/greet"},
+		{Range: lspRangeAt(10, 1, 10), Severity: lspSeverityError, Code: "unresolved-symbol", Source: "joker", Message: "Parse error: Unable to resolve symbol: undefined"},
+		{Range: lspRangeAt(8, 0, 4), Severity: lspSeverityWarning, Code: "unused-var", Source: "joker", Message: "Parse warning: unused var unused"},
+	}})
+	if msg["method"] != "textDocument/publishDiagnostics" || !reflect.DeepEqual(msg["params"], expected) {
+		t.Errorf("unexpected diagnostics %v", msg)
+	}
+
+	c.send(map[string]interface{}{"method": "textDocument/didChange", "params": map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": uri, "version": 2},
+		"contentChanges": []interface{}{map[string]interface{}{"text": lspTestText[:len(lspTestText)-len("(undefined)\n")]}}}})
+	msg = c.receive()
+	if diagnostics := msg["params"].(map[string]interface{})["diagnostics"].([]interface{}); len(diagnostics) != 2 {
+		t.Errorf("unexpected diagnostics after change %v", diagnostics)
+	}
+
+	res = c.request("textDocument/definition", at(9, 3))
+	if expected := toJSON(lspLocation{URI: uri, Range: lspRange{Start: lspPosition{Line: 3, Character: 0}, End: lspPosition{Line: 6, Character: 32}}}); !reflect.DeepEqual(res, expected) {
+		t.Errorf("unexpected definition %v", res)
+	}
+	if res = c.request("textDocument/definition", at(6, 5)); res != nil {
+		t.Errorf("unexpected definition %v", res)
+	}
+
+	res = c.request("textDocument/hover", at(9, 3))
+	expected = toJSON(map[string]interface{}{
+		"contents": map[string]interface{}{"kind": "markdown", "value": "```clojure\nlsp.test/greet\n```\n```clojure\n[who]\n```\n\nGreets who.\n"},
+		"range":    lspRangeAt(9, 1, 6),
+	})
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("unexpected hover %v", res)
+	}
+	res = c.request("textDocument/hover", at(3, 2))
+	if contents := res.(map[string]interface{})["contents"].(map[string]interface{}); !strings.HasPrefix(contents["value"].(string), "```clojure\njoker.core/defn\n```\n```clojure\n[name doc-string? attr-map? [params*] prepost-map? body]\n") {
+		t.Errorf("unexpected hover %q", contents["value"])
+	}
+
+	res = c.request("textDocument/documentSymbol", map[string]interface{}{"textDocument": map[string]interface{}{"uri": uri}})
+	expected = toJSON([]map[string]interface{}{
+		{"name": "greet", "kind": 12, "containerName": "lsp.test", "location": lspLocation{URI: uri, Range: lspRange{Start: lspPosition{Line: 3, Character: 0}, End: lspPosition{Line: 6, Character: 32}}}},
+		{"name": "unused", "kind": 13, "containerName": "lsp.test", "location": lspLocation{URI: uri, Range: lspRangeAt(8, 0, 24)}},
+	})
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("unexpected symbols %v", res)
+	}
+
+	res = c.request("textDocument/completion", at(6, 8))
+	if expected := toJSON([]map[string]interface{}{{"label": "str/join", "kind": 6}}); !reflect.DeepEqual(res, expected) {
+		t.Errorf("unexpected completions %v", res)
+	}
+	res = c.request("textDocument/completion", at(9, 4))
+	if expected := toJSON([]map[string]interface{}{{"label": "greet", "kind": 6}}); !reflect.DeepEqual(res, expected) {
+		t.Errorf("unexpected completions %v", res)
+	}
+
+	c.send(map[string]interface{}{"id": 100, "method": "no/such-method"})
+	if msg := c.receive(); msg["error"].(map[string]interface{})["code"] != float64(-32601) {
+		t.Errorf("unexpected response %v", msg)
+	}
+	c.request("shutdown", nil)
+	c.send(map[string]interface{}{"method": "exit"})
+	if err := c.cmd.Wait(); err != nil {
+		t.Errorf("unexpected exit: %v", err)
+	}
+}
+
+func TestLspUnterminatedForm(t *testing.T) {
+	c := startLsp(t)
+	uri := "file:///tmp/lsp-test/src/unterminated.clj"
+	c.request("initialize", map[string]interface{}{"rootUri": "file:///tmp/lsp-test"})
+	c.send(map[string]interface{}{"method": "textDocument/didOpen", "params": map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "clojure", "version": 1, "text": "(ns a)\n(defn f [x]\n  (let [y 1]\n"}}})
+	msg := c.receive()
+	expected := toJSON(map[string]interface{}{"uri": uri, "diagnostics": []lspDiagnostic{
+		{Range: lspRangeAt(3, 0, 1), Severity: lspSeverityError, Code: "read-error", Source: "joker", Message: "Read error: Unexpected end of file"},
+	}})
+	if msg["method"] != "textDocument/publishDiagnostics" || !reflect.DeepEqual(msg["params"], expected) {
+		t.Errorf("unexpected diagnostics %v", msg)
+	}
+}
+
+func TestLspInvalidContentLength(t *testing.T) {
+	for _, length := range []string{"-1", "99999999999"} {
+		c := startLsp(t)
+		if _, err := fmt.Fprintf(c.in, "Content-Length: %s\r\n\r\n{}", length); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.cmd.Wait(); err == nil {
+			t.Errorf("expected Content-Length %s to be rejected", length)
+		}
+	}
+}
@@ -6,13 +6,13 @@ import (
 	"fmt"
 	"io"
 	"math"
-	"net"
 	"os"
 	"path/filepath"
 	"runtime"
 	"runtime/pprof"
 	"strconv"
 	"strings"
+	"time"
 
 	. "github.com/candid82/joker/core"
 	_ "github.com/candid82/joker/std/base64"
@@ -67,6 +67,13 @@ type (
 		second *Var
 		third  *Var
 		exc    *Var
+		// If set, onRead is called with each form read, and onValue
+		// and onError with the value of each form and each error
+		// instead of printing them (e.g. to report them to nREPL
+		// or prepl clients).
+		onRead  func(Object)
+		onValue func(Object)
+		onError func(error)
 	}
 )
 
@@ -97,6 +104,61 @@ func (ctx *ReplContext) PushException(exc Object) {
 	ctx.exc.Value = exc
 }
 
+func (ctx *ReplContext) reportError(err error) {
+	if ctx.onError != nil {
+		ctx.onError(err)
+		return
+	}
+	fmt.Fprintln(Stderr, err)
+}
+
+// replSession is the state of a network repl client: the namespace,
+// repl values and streams that are swapped in while evaluating its
+// code, so that clients don't step on each other.
+type replSession struct {
+	ns     *Namespace // nil until the session is first entered
+	values [4]Object  // *1, *2, *3 and *e
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	stdio  [3]Object // *in*, *out* and *err*
+}
+
+func newReplSession() replSession {
+	return replSession{values: [4]Object{NIL, NIL, NIL, NIL}}
+}
+
+func (session *replSession) setStdIO(stdin io.Reader, stdout, stderr io.Writer) {
+	session.stdin, session.stdout, session.stderr = stdin, stdout, stderr
+	session.stdio = [3]Object{MakeBufferedReader(stdin), MakeIOWriter(stdout), MakeIOWriter(stderr)}
+}
+
+// enter switches to the session, with the GIL held. The returned
+// function saves the session's state and switches back.
+func (session *replSession) enter(ctx *ReplContext) (leave func()) {
+	if session.ns == nil {
+		session.ns = GLOBAL_ENV.FindNamespace(MakeSymbol("user"))
+	}
+	oldNs := GLOBAL_ENV.CurrentNamespace()
+	oldValues := [4]Object{ctx.first.Value, ctx.second.Value, ctx.third.Value, ctx.exc.Value}
+	oldStdin, oldStdout, oldStderr := Stdin, Stdout, Stderr
+	oldStdinValue, oldStdoutValue, oldStderrValue := GLOBAL_ENV.StdIO()
+
+	GLOBAL_ENV.SetCurrentNamespace(session.ns)
+	ctx.first.Value, ctx.second.Value, ctx.third.Value, ctx.exc.Value = session.values[0], session.values[1], session.values[2], session.values[3]
+	Stdin, Stdout, Stderr = session.stdin, session.stdout, session.stderr
+	GLOBAL_ENV.SetStdIO(session.stdio[0], session.stdio[1], session.stdio[2])
+
+	return func() {
+		session.ns = GLOBAL_ENV.CurrentNamespace()
+		session.values = [4]Object{ctx.first.Value, ctx.second.Value, ctx.third.Value, ctx.exc.Value}
+		GLOBAL_ENV.SetCurrentNamespace(oldNs)
+		ctx.first.Value, ctx.second.Value, ctx.third.Value, ctx.exc.Value = oldValues[0], oldValues[1], oldValues[2], oldValues[3]
+		Stdin, Stdout, Stderr = oldStdin, oldStdout, oldStderr
+		GLOBAL_ENV.SetStdIO(oldStdinValue, oldStdoutValue, oldStderrValue)
+	}
+}
+
 func processFile(filename string, phase Phase) error {
 	var reader *Reader
 	if filename == "-" {
@@ -153,13 +215,13 @@ func processReplCommand(reader *Reader, phase Phase, parseContext *ParseContext,
 			switch r := r.(type) {
 			case *ParseError:
 				replContext.PushException(r)
-				fmt.Fprintln(Stderr, r)
+				replContext.reportError(r)
 			case *EvalError:
 				replContext.PushException(r)
-				fmt.Fprintln(Stderr, r)
+				replContext.reportError(r)
 			case Error:
 				replContext.PushException(r)
-				fmt.Fprintln(Stderr, r)
+				replContext.reportError(r)
 				// case *runtime.TypeAssertionError:
 				// 	fmt.Fprintln(Stderr, r)
 			default:
@@ -173,16 +235,26 @@ func processReplCommand(reader *Reader, phase Phase, parseContext *ParseContext,
 		return true
 	}
 	if err != nil {
-		fmt.Fprintln(Stderr, err)
+		replContext.reportError(err)
 		skipRestOfLine(reader)
 		return
 	}
+	if replContext.onRead != nil {
+		replContext.onRead(obj)
+	}
 
 	if phase == READ {
 		fmt.Println(obj.ToString(true))
 		return false
 	}
 
+	// In the repl, --timeout, --max-steps and --max-memory apply to each form separately.
+	RT.ResetLimits()
+	RT.SetTimeout(timeout)
+	RT.SetMaxSteps(maxSteps)
+	RT.SetMaxMemory(maxMemory)
+	defer RT.ResetLimits()
+
 	expr := Parse(obj, parseContext)
 	if phase == PARSE {
 		fmt.Println(expr)
@@ -191,71 +263,15 @@ func processReplCommand(reader *Reader, phase Phase, parseContext *ParseContext,
 
 	res := Eval(expr, nil)
 	replContext.PushValue(res)
+	if replContext.onValue != nil {
+		replContext.onValue(res)
+		return false
+	}
 	PrintObject(res, Stdout)
 	fmt.Fprintln(Stdout, "")
 	return false
 }
 
-func srepl(port string, phase Phase) {
-	ProcessReplData()
-	GLOBAL_ENV.FindNamespace(MakeSymbol("user")).ReferAll(GLOBAL_ENV.FindNamespace(MakeSymbol("joker.repl")))
-	GLOBAL_ENV.CoreNamespace.Resolve("*repl*").Value = Boolean{B: true}
-	l, err := net.Listen("tcp", replSocket)
-	if err != nil {
-		fmt.Fprintf(Stderr, "Cannot start srepl listening on %s: %s\n",
-			replSocket, err.Error())
-		ExitJoker(12)
-	}
-	defer l.Close()
-
-	fmt.Printf("Joker repl listening at %s...\n", l.Addr())
-	conn, err := l.Accept() // Wait for a single connection
-	if err != nil {
-		fmt.Fprintf(Stderr, "Cannot start repl accepting on %s: %s\n",
-			l.Addr(), err.Error())
-		ExitJoker(13)
-	}
-
-	oldStdIn := Stdin
-	oldStdOut := Stdout
-	oldStdErr := Stderr
-	oldStdinValue, oldStdoutValue, oldStderrValue := GLOBAL_ENV.StdIO()
-	Stdin = conn
-	Stdout = conn
-	Stderr = conn
-	newIn := MakeBufferedReader(conn)
-	newOut := MakeIOWriter(conn)
-	GLOBAL_ENV.SetStdIO(newIn, newOut, newOut)
-	defer func() {
-		conn.Close()
-		Stdin = oldStdIn
-		Stdout = oldStdOut
-		Stderr = oldStdErr
-		GLOBAL_ENV.SetStdIO(oldStdinValue, oldStdoutValue, oldStderrValue)
-	}()
-
-	fmt.Printf("Joker repl accepting client at %s...\n", conn.RemoteAddr())
-
-	runeReader := bufio.NewReader(conn)
-
-	/* The rest of this code comes from repl(), below: */
-
-	parseContext := &ParseContext{GlobalEnv: GLOBAL_ENV}
-	replContext := NewReplContext(parseContext.GlobalEnv)
-
-	reader := NewReader(runeReader, "<srepl>")
-
-	fmt.Fprintf(Stdout, "Welcome to joker %s, client at %s. Use '(exit)', or close the connection, to exit.\n",
-		VERSION, conn.RemoteAddr())
-
-	for {
-		fmt.Fprint(Stdout, GLOBAL_ENV.CurrentNamespace().Name.ToString(false)+"=> ")
-		if processReplCommand(reader, phase, parseContext, replContext) {
-			return
-		}
-	}
-}
-
 func makeDialectKeyword(dialect Dialect) Keyword {
 	switch dialect {
 	case EDN:
@@ -385,10 +401,13 @@ func usage(out io.Writer) {
 	fmt.Fprintln(out, "Usage: joker [args] [-- <repl-args>]                starts a repl")
 	fmt.Fprintln(out, "   or: joker [args] --repl [<socket>] [-- <repl-args>]")
 	fmt.Fprintln(out, "                                                    starts a repl (on optional network socket)")
+	fmt.Fprintln(out, "   or: joker [args] --prepl <socket>                starts a repl sending EDN-tagged results")
+	fmt.Fprintln(out, "   or: joker [args] --nrepl [<addr>]                 starts an nREPL server for editors")
 	fmt.Fprintln(out, "   or: joker [args] --eval <expr> [-- <expr-args>]  evaluate <expr>, print if non-nil")
 	fmt.Fprintln(out, "   or: joker [args] [--file] <filename> [<script-args>]")
 	fmt.Fprintln(out, "                                                    input from file")
 	fmt.Fprintln(out, "   or: joker [args] --lint <filename>               lint the code in file")
+	fmt.Fprintln(out, "   or: joker [args] --lsp                           starts a language server (LSP) on stdio")
 	fmt.Fprintln(out, "\nNotes:")
 	fmt.Fprintln(out, "  -e is a synonym for --eval.")
 	fmt.Fprintln(out, "  '-' for <filename> means read from standard input (stdin).")
@@ -396,6 +415,9 @@ func usage(out io.Writer) {
 	fmt.Fprintln(out, "    in <repl-args>, <expr-args>, or <script-args> (TBD).")
 	fmt.Fprintln(out, "  <socket> is passed to Go's net.Listen() function. If multiple --*repl options are specified,")
 	fmt.Fprintln(out, "    the final one specified \"wins\".")
+	fmt.Fprintln(out, "  A repl on a socket serves any number of clients, each with its own *ns*, *1, *2, *3 and *e.")
+	fmt.Fprintln(out, "  --prepl sends each result as {:tag :ret :val <printed value> :ns <ns> :ms <ms> :form <form>},")
+	fmt.Fprintln(out, "    and output as {:tag :out :val <text>} or {:tag :err :val <text>}.")
 
 	fmt.Fprintln(out, "\nOptions (<args>):")
 	fmt.Fprintln(out, "  --help, -h")
@@ -421,14 +443,38 @@ func usage(out io.Writer) {
 	fmt.Fprintln(out, "  --no-repl-history")
 	fmt.Fprintln(out, "    Do not read or save repl command history to a file.")
 	fmt.Fprintln(out, "  --working-dir <directory>")
-	fmt.Fprintln(out, "    Specify directory to lint or working directory for lint configuration if linting single file (requires --lint).")
+	fmt.Fprintln(out, "    Specify directory to lint or working directory for lint configuration if linting single file (requires --lint or --lsp).")
 	fmt.Fprintln(out, "  --report-globally-unused")
 	fmt.Fprintln(out, "    Report globally unused namespaces and public vars when linting directories (requires --lint and --working-dir).")
+	fmt.Fprintln(out, "  --lint-output <format>, --lint-output=<format>")
+	fmt.Fprintln(out, "    Print linter problems to stdout as \"json\", \"sarif\" or \"edn\" records instead of")
+	fmt.Fprintln(out, "    text to stderr (\"text\", the default); each has a code that can be referenced in config.")
 	fmt.Fprintln(out, "  --dialect <dialect>")
 	fmt.Fprintln(out, "    Set input dialect (\"clj\", \"cljs\", \"joker\", \"edn\") for linting;")
 	fmt.Fprintln(out, "    default is inferred from <filename> suffix, if any.")
 	fmt.Fprintln(out, "  --hashmap-threshold <n>")
 	fmt.Fprintln(out, "    Set HASHMAP_THRESHOLD accordingly (internal magic of some sort).")
+	fmt.Fprintln(out, "  --max-call-depth <n>")
+	fmt.Fprintf(out, "    Set *max-call-depth*, the call depth beyond which StackOverflowError is thrown\n")
+	fmt.Fprintf(out, "    (default %d; 0 means no limit).\n", DEFAULT_MAX_CALL_DEPTH)
+	fmt.Fprintln(out, "  --debug-repl")
+	fmt.Fprintln(out, "    Pause evaluation at breakpoints and calls to (break) and start the debugger repl,")
+	fmt.Fprintln(out, "    where locals can be inspected and expressions evaluated (type :help there).")
+	fmt.Fprintln(out, "  --break <ns/name|file:line>")
+	fmt.Fprintln(out, "    Like --debug-repl, setting a breakpoint on calls of a var or on a line (may be repeated).")
+	fmt.Fprintln(out, "  --sandbox")
+	fmt.Fprintln(out, "    Disallow access to the file system, network and other processes: calling e.g.")
+	fmt.Fprintln(out, "    joker.os/sh, joker.http/send, slurp, spit or load-file throws an error.")
+	fmt.Fprintln(out, "  --sandbox-config <filename>")
+	fmt.Fprintln(out, "    Like --sandbox, additionally allowing or denying the namespaces and vars listed")
+	fmt.Fprintln(out, "    in the map read from <filename>, e.g. {:allow [joker.os/env] :deny [joker.time/sleep]}.")
+	fmt.Fprintln(out, "  --timeout <duration>")
+	fmt.Fprintln(out, "    Throw InterruptedError if evaluation takes longer than <duration> (e.g. \"500ms\", \"10s\").")
+	fmt.Fprintln(out, "  --max-steps <n>")
+	fmt.Fprintln(out, "    Throw InterruptedError after <n> evaluation steps (function calls, loop iterations")
+	fmt.Fprintln(out, "    and lazy seq realizations).")
+	fmt.Fprintln(out, "  --max-memory <bytes>")
+	fmt.Fprintln(out, "    Throw InterruptedError if the heap grows beyond <bytes>.")
 	fmt.Fprintln(out, "  --profiler <type>")
 	fmt.Fprintln(out, "    Specify type of profiler to use (default 'runtime/pprof' or 'pkg/profile').")
 	fmt.Fprintln(out, "  --cpuprofile <name>")
@@ -450,10 +496,15 @@ var (
 	phase                    Phase = EVAL // --read, --parse, --evaluate
 	workingDir               string
 	lintFlag                 bool
+	lintOutput               string = "text"
+	lspFlag                  bool
 	reportGloballyUnusedFlag bool
 	dialect                  Dialect = UNKNOWN
 	eval                     string
 	replFlag                 bool
+	nreplFlag                bool
+	nreplAddr                string = "127.0.0.1:0"
+	preplFlag                bool
 	replSocket               string
 	classPath                string
 	filename                 string
@@ -468,6 +519,14 @@ var (
 	exitToRepl               bool
 	errorToRepl              bool
 	writeFlag                bool
+	maxCallDepth             int = DEFAULT_MAX_CALL_DEPTH
+	timeout                  time.Duration
+	maxSteps                 int64
+	maxMemory                uint64
+	sandboxFlag              bool
+	debugRepl                bool
+	breakpoints              []string
+	sandboxConfig            string
 )
 
 func isNumber(s string) bool {
@@ -559,6 +618,15 @@ func parseArgs(args []string) {
 			reportGloballyUnusedFlag = true
 		case "--lint":
 			lintFlag = true
+		case "--lint-output":
+			if i < length-1 && notOption(args[i+1]) {
+				i += 1 // shift
+				lintOutput = args[i]
+			} else {
+				missing = true
+			}
+		case "--lsp":
+			lspFlag = true
 		case "--lintclj":
 			lintFlag = true
 			dialect = CLJ
@@ -594,6 +662,74 @@ func parseArgs(args []string) {
 			} else {
 				missing = true
 			}
+		case "--max-call-depth":
+			if i < length-1 && notOption(args[i+1]) {
+				i += 1 // shift
+				depth, err := strconv.ParseInt(args[i], 10, 64)
+				if err != nil {
+					fmt.Fprintln(Stderr, "Error: ", err)
+					ExitJoker(3)
+				}
+				maxCallDepth = int(depth)
+			} else {
+				missing = true
+			}
+		case "--debug-repl":
+			debugRepl = true
+		case "--break":
+			if i < length-1 && notOption(args[i+1]) {
+				i += 1 // shift
+				debugRepl = true
+				breakpoints = append(breakpoints, args[i])
+			} else {
+				missing = true
+			}
+		case "--sandbox":
+			sandboxFlag = true
+		case "--sandbox-config":
+			if i < length-1 && notOption(args[i+1]) {
+				i += 1 // shift
+				sandboxFlag = true
+				sandboxConfig = args[i]
+			} else {
+				missing = true
+			}
+		case "--timeout":
+			if i < length-1 && notOption(args[i+1]) {
+				i += 1 // shift
+				d, err := time.ParseDuration(args[i])
+				if err != nil {
+					fmt.Fprintln(Stderr, "Error: ", err)
+					ExitJoker(3)
+				}
+				timeout = d
+			} else {
+				missing = true
+			}
+		case "--max-steps":
+			if i < length-1 && notOption(args[i+1]) {
+				i += 1 // shift
+				steps, err := strconv.ParseInt(args[i], 10, 64)
+				if err != nil {
+					fmt.Fprintln(Stderr, "Error: ", err)
+					ExitJoker(3)
+				}
+				maxSteps = steps
+			} else {
+				missing = true
+			}
+		case "--max-memory":
+			if i < length-1 && notOption(args[i+1]) {
+				i += 1 // shift
+				bytes, err := strconv.ParseUint(args[i], 10, 64)
+				if err != nil {
+					fmt.Fprintln(Stderr, "Error: ", err)
+					ExitJoker(3)
+				}
+				maxMemory = bytes
+			} else {
+				missing = true
+			}
 		case "-e", "--eval":
 			if i < length-1 && notOption(args[i+1]) {
 				i += 1 // shift
@@ -608,6 +744,21 @@ func parseArgs(args []string) {
 				i += 1 // shift
 				replSocket = args[i]
 			}
+		case "--prepl":
+			replFlag = true
+			preplFlag = true
+			if i < length-1 && notOption(args[i+1]) {
+				i += 1 // shift
+				replSocket = args[i]
+			} else {
+				missing = true
+			}
+		case "--nrepl":
+			nreplFlag = true
+			if i < length-1 && notOption(args[i+1]) {
+				i += 1 // shift
+				nreplAddr = args[i]
+			}
 		case "-c", "--classpath":
 			if i < length-1 && notOption(args[i+1]) {
 				i += 1 // shift
@@ -687,6 +838,10 @@ func parseArgs(args []string) {
 				missing = true
 			}
 		default:
+			if strings.HasPrefix(args[i], "--lint-output=") {
+				lintOutput = strings.TrimPrefix(args[i], "--lint-output=")
+				break
+			}
 			if strings.HasPrefix(args[i], "-") {
 				fmt.Fprintf(Stderr, "Error: Unrecognized option '%s'\n", args[i])
 				ExitJoker(2)
@@ -720,6 +875,32 @@ var runningProfile interface {
 	Stop()
 }
 
+func enableSandbox() {
+	sb := NewSandbox()
+	if sandboxConfig != "" {
+		config, err := ReadSandboxConfig(sandboxConfig)
+		if err == nil {
+			err = sb.Configure(config)
+		}
+		if err != nil {
+			fmt.Fprintln(Stderr, "Error reading sandbox config file "+sandboxConfig+": ", err)
+			ExitJoker(3)
+		}
+	}
+	EnableSandbox(sb)
+}
+
+func enableDebugger() {
+	debugger := NewDebugger()
+	for _, target := range breakpoints {
+		if err := debugger.AddBreakpoint(target); err != nil {
+			fmt.Fprintln(Stderr, "Error: ", err)
+			ExitJoker(3)
+		}
+	}
+	EnableDebugger(debugger)
+}
+
 func main() {
 	OnExit(finish)
 
@@ -730,11 +911,23 @@ func main() {
 	saveForRepl = saveForRepl && (exitToRepl || errorToRepl) // don't bother saving stuff if no repl
 
 	RT.GIL.Lock()
+	// Let queued agent actions complete when exiting normally (but not via ExitJoker).
+	defer WaitForAgents()
 	ProcessCoreData()
 
 	GLOBAL_ENV.ReferCoreToUser()
 	GLOBAL_ENV.SetEnvArgs(remainingArgs)
 	GLOBAL_ENV.SetClassPath(classPath)
+	GLOBAL_ENV.SetMaxCallDepth(maxCallDepth)
+	if sandboxFlag {
+		enableSandbox()
+	}
+	if debugRepl {
+		enableDebugger()
+	}
+	RT.SetTimeout(timeout)
+	RT.SetMaxSteps(maxSteps)
+	RT.SetMaxMemory(maxMemory)
 
 	if debugOut != nil {
 		fmt.Fprintf(debugOut, "debugOut=%v\n", debugOut)
@@ -742,13 +935,26 @@ func main() {
 		fmt.Fprintf(debugOut, "versionFlag=%v\n", versionFlag)
 		fmt.Fprintf(debugOut, "phase=%v\n", phase)
 		fmt.Fprintf(debugOut, "lintFlag=%v\n", lintFlag)
+		fmt.Fprintf(debugOut, "lintOutput=%v\n", lintOutput)
+		fmt.Fprintf(debugOut, "lspFlag=%v\n", lspFlag)
 		fmt.Fprintf(debugOut, "reportGloballyUnusedFlag=%v\n", reportGloballyUnusedFlag)
 		fmt.Fprintf(debugOut, "dialect=%v\n", dialect)
 		fmt.Fprintf(debugOut, "workingDir=%v\n", workingDir)
 		fmt.Fprintf(debugOut, "HASHMAP_THRESHOLD=%v\n", HASHMAP_THRESHOLD)
+		fmt.Fprintf(debugOut, "maxCallDepth=%v\n", maxCallDepth)
+		fmt.Fprintf(debugOut, "timeout=%v\n", timeout)
+		fmt.Fprintf(debugOut, "maxSteps=%v\n", maxSteps)
+		fmt.Fprintf(debugOut, "maxMemory=%v\n", maxMemory)
+		fmt.Fprintf(debugOut, "sandboxFlag=%v\n", sandboxFlag)
+		fmt.Fprintf(debugOut, "debugRepl=%v\n", debugRepl)
+		fmt.Fprintf(debugOut, "breakpoints=%v\n", breakpoints)
+		fmt.Fprintf(debugOut, "sandboxConfig=%v\n", sandboxConfig)
 		fmt.Fprintf(debugOut, "eval=%v\n", eval)
 		fmt.Fprintf(debugOut, "replFlag=%v\n", replFlag)
 		fmt.Fprintf(debugOut, "replSocket=%v\n", replSocket)
+		fmt.Fprintf(debugOut, "nreplFlag=%v\n", nreplFlag)
+		fmt.Fprintf(debugOut, "nreplAddr=%v\n", nreplAddr)
+		fmt.Fprintf(debugOut, "preplFlag=%v\n", preplFlag)
 		fmt.Fprintf(debugOut, "classPath=%v\n", classPath)
 		fmt.Fprintf(debugOut, "noReadline=%v\n", noReadline)
 		fmt.Fprintf(debugOut, "noReplHistory=%v\n", noReplHistory)
@@ -812,6 +1018,15 @@ func main() {
 		defer finish()
 	}
 
+	if lspFlag {
+		if eval != "" || lintFlag || replFlag || nreplFlag || filename != "" {
+			fmt.Fprintf(Stderr, "Error: Cannot combine --lsp with --eval/-e, --lint, --repl, --nrepl or a <filename> argument.\n")
+			ExitJoker(20)
+		}
+		lsp(dialect, workingDir)
+		return
+	}
+
 	if eval != "" {
 		if lintFlag {
 			fmt.Fprintf(Stderr, "Error: Cannot combine --eval/-e and --lint.\n")
@@ -821,6 +1036,10 @@ func main() {
 			fmt.Fprintf(Stderr, "Error: Cannot combine --eval/-e and --repl.\n")
 			ExitJoker(7)
 		}
+		if nreplFlag {
+			fmt.Fprintf(Stderr, "Error: Cannot combine --eval/-e and --nrepl.\n")
+			ExitJoker(18)
+		}
 		if workingDir != "" {
 			fmt.Fprintf(Stderr, "Error: Cannot combine --eval/-e and --working-dir.\n")
 			ExitJoker(8)
@@ -853,6 +1072,10 @@ func main() {
 			fmt.Fprintf(Stderr, "Error: Cannot combine --lint and --repl.\n")
 			ExitJoker(10)
 		}
+		if nreplFlag {
+			fmt.Fprintf(Stderr, "Error: Cannot combine --lint and --nrepl.\n")
+			ExitJoker(19)
+		}
 		if exitToRepl {
 			fmt.Fprintf(Stderr, "Error: Cannot combine --lint and --exit-to-repl.\n")
 			ExitJoker(14)
@@ -861,6 +1084,12 @@ func main() {
 			fmt.Fprintf(Stderr, "Error: Cannot combine --lint and --error-to-repl.\n")
 			ExitJoker(15)
 		}
+		writeLintOutput, ok := lintOutputFormats[lintOutput]
+		if !ok && lintOutput != "text" {
+			fmt.Fprintf(Stderr, "Error: Unknown lint output format '%s' (expected text, json, sarif or edn).\n", lintOutput)
+			ExitJoker(21)
+		}
+		COLLECT_PROBLEMS = ok
 		if dialect == UNKNOWN {
 			dialect = detectDialect(filename)
 		}
@@ -872,6 +1101,9 @@ func main() {
 			fmt.Fprintf(Stderr, "Error: Missing --file or --working-dir argument.\n")
 			ExitJoker(16)
 		}
+		if ok {
+			writeLintOutput(Stdout, PROBLEMS)
+		}
 		if PROBLEM_COUNT > 0 {
 			ExitJoker(1)
 		}
@@ -895,8 +1127,13 @@ func main() {
 		}
 	}
 
+	if nreplFlag {
+		nrepl(nreplAddr)
+		return
+	}
+
 	if replSocket != "" {
-		srepl(replSocket, phase)
+		srepl(replSocket, phase, preplFlag)
 		return
 	}
 
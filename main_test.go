@@ -0,0 +1,41 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/candid82/joker/core"
+)
+
+func TestMain(m *testing.M) {
+	GLOBAL_ENV.InitEnv(Stdin, Stdout, Stderr, nil)
+	ProcessCoreData()
+	GLOBAL_ENV.ReferCoreToUser()
+	if os.Getenv("JOKER_TEST_LSP") != "" {
+		// Linter mode changes global state, so the language server
+		// is tested in a separate process (see startLsp).
+		RT.GIL.Lock()
+		lsp(UNKNOWN, "")
+	}
+	os.Exit(m.Run())
+}
+
+func TestMaxMemory(t *testing.T) {
+	RT.GIL.Lock()
+	defer RT.GIL.Unlock()
+	obj, err := TryRead(NewReader(strings.NewReader("(loop [acc []] (recur (conj acc 1)))"), "<test>"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expr, err := TryParse(obj, &ParseContext{GlobalEnv: GLOBAL_ENV})
+	if err != nil {
+		t.Fatal(err)
+	}
+	RT.SetMaxMemory(20 << 20)
+	defer RT.ResetLimits()
+	_, err = TryEval(expr)
+	if _, ok := err.(*InterruptedError); !ok || !strings.Contains(err.Error(), "Evaluation exceeded the memory limit of 20971520 bytes") {
+		t.Errorf("expected memory limit error, got %v", err)
+	}
+}
@@ -0,0 +1,491 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	. "github.com/candid82/joker/core"
+)
+
+// nREPL server (https://nrepl.org), so that editors like CIDER, Calva
+// and Conjure can drive Joker. Each session has its own *ns*, *1, *2,
+// *3 and *e, which are swapped in while one of its requests is being
+// evaluated. Requests are evaluated one at a time (holding the GIL),
+// in order within each session.
+
+type (
+	nreplMsg = map[string]interface{}
+
+	nreplServer struct {
+		replContext *ReplContext
+		mutex       sync.Mutex // guards the fields below
+		sessions    map[string]*nreplSession
+		running     *nreplRequest
+		lastID      int
+	}
+
+	nreplSession struct {
+		replSession
+		id       string
+		requests chan *nreplRequest
+	}
+
+	nreplRequest struct {
+		msg     nreplMsg
+		conn    *nreplConn
+		session *nreplSession
+	}
+
+	// nreplConn serializes writes of responses to a connection.
+	nreplConn struct {
+		mutex sync.Mutex
+		w     io.Writer
+	}
+
+	// nreplWriter sends what is written to it as out or err responses.
+	nreplWriter struct {
+		req    *nreplRequest
+		stream string
+	}
+)
+
+var nreplOps = []string{"clone", "close", "completions", "describe", "eldoc", "eval", "info", "interrupt", "load-file", "lookup"}
+
+func newNreplServer() *nreplServer {
+	return &nreplServer{
+		replContext: NewReplContext(GLOBAL_ENV),
+		sessions:    make(map[string]*nreplSession),
+	}
+}
+
+// nrepl runs an nREPL server listening on addr ("host:port" or "port")
+// until Joker exits.
+func nrepl(addr string) {
+	ProcessReplData()
+	GLOBAL_ENV.FindNamespace(MakeSymbol("user")).ReferAll(GLOBAL_ENV.FindNamespace(MakeSymbol("joker.repl")))
+	GLOBAL_ENV.CoreNamespace.Resolve("*repl*").Value = Boolean{B: true}
+	if _, err := strconv.Atoi(addr); err == nil {
+		addr = "127.0.0.1:" + addr
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		fmt.Fprintf(Stderr, "Cannot start nREPL server listening on %s: %s\n", addr, err.Error())
+		ExitJoker(12)
+	}
+	defer l.Close()
+	tcpAddr := l.Addr().(*net.TCPAddr)
+	// Editors look for .nrepl-port to find the server to connect to.
+	if err := os.WriteFile(".nrepl-port", []byte(strconv.Itoa(tcpAddr.Port)), 0666); err == nil {
+		OnExit(func() { os.Remove(".nrepl-port") })
+		defer os.Remove(".nrepl-port")
+	}
+	fmt.Printf("nREPL server started on port %d on host %s - nrepl://%s\n", tcpAddr.Port, tcpAddr.IP, tcpAddr)
+
+	server := newNreplServer()
+	RT.GIL.Unlock()
+	defer RT.GIL.Lock()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			fmt.Fprintf(Stderr, "Cannot accept nREPL connection on %s: %s\n", l.Addr(), err.Error())
+			return
+		}
+		go server.serve(conn)
+	}
+}
+
+// serve reads requests from conn until it's closed.
+func (s *nreplServer) serve(conn io.ReadWriteCloser) {
+	defer conn.Close()
+	c := &nreplConn{w: conn}
+	r := bufio.NewReader(conn)
+	for {
+		v, err := bencodeRead(r)
+		if err != nil {
+			if err != io.EOF {
+				fmt.Fprintln(Stderr, "nREPL:", err)
+			}
+			return
+		}
+		if msg, ok := v.(map[string]interface{}); ok {
+			s.handle(&nreplRequest{msg: msg, conn: c})
+		}
+	}
+}
+
+func (s *nreplServer) handle(req *nreplRequest) {
+	if id := req.get("session"); id != "" {
+		s.mutex.Lock()
+		req.session = s.sessions[id]
+		s.mutex.Unlock()
+		if req.session == nil {
+			req.reply(nreplMsg{"status": []string{"error", "unknown-session", "done"}})
+			return
+		}
+	}
+	switch op := req.get("op"); op {
+	case "clone":
+		session := s.newSession(req.session)
+		req.reply(nreplMsg{"new-session": session.id, "status": []string{"done"}})
+	case "close":
+		if req.session != nil {
+			s.closeSession(req.session)
+		}
+		req.reply(nreplMsg{"status": []string{"session-closed", "done"}})
+	case "describe":
+		ops := nreplMsg{}
+		for _, op := range nreplOps {
+			ops[op] = nreplMsg{}
+		}
+		req.reply(nreplMsg{
+			"ops": ops,
+			"versions": map[string]interface{}{
+				"joker": map[string]interface{}{"version-string": VERSION},
+				"nrepl": map[string]interface{}{"major": 1, "minor": 0, "incremental": 0, "version-string": "1.0.0"},
+			},
+			"status": []string{"done"},
+		})
+	case "eval", "load-file":
+		if req.session == nil {
+			// Requests without a session are evaluated in a new one that's then discarded.
+			req.session = s.newSession(nil)
+			defer s.closeSession(req.session)
+		}
+		req.session.requests <- req
+	case "interrupt":
+		s.interrupt(req)
+	case "completions", "complete", "info", "eldoc", "lookup":
+		// Handled in a goroutine so that an interrupt can still be read
+		// while waiting for the GIL.
+		go s.lookup(req)
+	default:
+		req.reply(nreplMsg{"status": []string{"error", "unknown-op", "done"}})
+	}
+}
+
+func (s *nreplServer) newSession(parent *nreplSession) *nreplSession {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.lastID++
+	session := &nreplSession{
+		replSession: newReplSession(),
+		id:          strconv.Itoa(s.lastID),
+		requests:    make(chan *nreplRequest, 16),
+	}
+	if parent != nil {
+		// Guarded by s.mutex, see enter.
+		session.ns = parent.ns
+		session.values = parent.values
+	}
+	s.sessions[session.id] = session
+	go s.run(session)
+	return session
+}
+
+func (s *nreplServer) closeSession(session *nreplSession) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.sessions[session.id] == session {
+		delete(s.sessions, session.id)
+		close(session.requests)
+	}
+}
+
+// run evaluates session's requests one by one until it's closed.
+func (s *nreplServer) run(session *nreplSession) {
+	for req := range session.requests {
+		s.eval(req)
+	}
+}
+
+func (s *nreplServer) eval(req *nreplRequest) {
+	RT.GIL.Lock()
+	defer RT.GIL.Unlock()
+	s.setRunning(req)
+	defer s.setRunning(nil)
+	defer s.enter(req)()
+
+	code, filename := req.get("code"), "<nrepl>"
+	if req.get("op") == "load-file" {
+		code = req.get("file")
+		if filename = req.get("file-path"); filename == "" {
+			filename = req.get("file-name")
+		}
+	} else if file := req.get("file"); file != "" {
+		filename = file
+	}
+	if nsName := req.get("ns"); nsName != "" {
+		ns := GLOBAL_ENV.FindNamespace(MakeSymbol(nsName))
+		if ns == nil {
+			req.reply(nreplMsg{"status": []string{"error", "namespace-not-found", "done"}})
+			return
+		}
+		GLOBAL_ENV.SetCurrentNamespace(ns)
+	}
+
+	var last Object
+	status := []string{"done"}
+	// The copy has the same *1, *2, *3 and *e vars, but hooks of its own.
+	replContext := *s.replContext
+	replContext.onValue = func(obj Object) {
+		if req.get("op") == "load-file" {
+			last = obj
+			return
+		}
+		req.replyValue(obj)
+	}
+	replContext.onError = func(err error) {
+		fmt.Fprintln(Stderr, err)
+		if _, ok := err.(*InterruptedError); ok {
+			status = []string{"interrupted", "done"}
+			return
+		}
+		status = []string{"eval-error", "done"}
+		typeName := "Error"
+		if obj, ok := err.(Object); ok {
+			typeName = obj.GetType().ToString(false)
+		}
+		req.reply(nreplMsg{"ex": typeName, "root-ex": typeName})
+	}
+
+	reader := NewReader(strings.NewReader(code), filename)
+	parseContext := &ParseContext{GlobalEnv: GLOBAL_ENV}
+	for status[0] != "interrupted" && !processReplCommand(reader, EVAL, parseContext, &replContext) {
+	}
+	if last != nil {
+		req.replyValue(last)
+	}
+	req.reply(nreplMsg{"status": status})
+}
+
+// enter switches to req's session, with output streams sending out
+// and err responses to req. The returned function switches back, saving
+// the session's state under s.mutex so that clone can copy it.
+func (s *nreplServer) enter(req *nreplRequest) func() {
+	req.session.setStdIO(strings.NewReader(""), &nreplWriter{req: req, stream: "out"}, &nreplWriter{req: req, stream: "err"})
+	leave := req.session.enter(s.replContext)
+	return func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		leave()
+	}
+}
+
+func (s *nreplServer) setRunning(req *nreplRequest) {
+	s.mutex.Lock()
+	s.running = req
+	s.mutex.Unlock()
+}
+
+func (s *nreplServer) interrupt(req *nreplRequest) {
+	s.mutex.Lock()
+	running := s.running
+	s.mutex.Unlock()
+	id := req.get("interrupt-id")
+	if running == nil || running.session != req.session || (id != "" && id != running.get("id")) {
+		req.reply(nreplMsg{"status": []string{"session-idle", "done"}})
+		return
+	}
+	RT.Interrupt()
+	req.reply(nreplMsg{"status": []string{"done"}})
+}
+
+// lookup handles the completions, info, eldoc and lookup ops.
+func (s *nreplServer) lookup(req *nreplRequest) {
+	RT.GIL.Lock()
+	defer RT.GIL.Unlock()
+	ns := GLOBAL_ENV.FindNamespace(MakeSymbol(req.get("ns")))
+	if ns == nil && req.session != nil {
+		ns = req.session.ns
+	}
+	if ns == nil {
+		ns = GLOBAL_ENV.FindNamespace(MakeSymbol("user"))
+	}
+	op := req.get("op")
+	if op == "completions" || op == "complete" {
+		prefix := req.get("prefix")
+		if prefix == "" {
+			prefix = req.get("symbol")
+		}
+		req.reply(nreplMsg{"completions": nreplCompletions(ns, prefix), "status": []string{"done"}})
+		return
+	}
+	sym := req.get("sym")
+	if sym == "" {
+		sym = req.get("symbol")
+	}
+	var vr *Var
+	if sym != "" {
+		vr, _ = GLOBAL_ENV.ResolveIn(ns, MakeSymbol(sym))
+	}
+	if vr == nil {
+		req.reply(nreplMsg{"status": []string{"no-info", "done"}})
+		return
+	}
+	info := nreplVarInfo(vr)
+	switch op {
+	case "lookup":
+		req.reply(nreplMsg{"info": info, "status": []string{"done"}})
+	case "eldoc":
+		res := nreplMsg{"name": info["name"], "ns": info["ns"], "type": "function", "status": []string{"done"}}
+		if _, ok := info["macro"]; ok {
+			res["type"] = "macro"
+		} else if _, ok := info["arglists-str"]; !ok {
+			res["type"] = "variable"
+		}
+		if doc, ok := info["doc"]; ok {
+			res["docstring"] = doc
+		}
+		eldoc := []interface{}{}
+		if arglists, ok := metaValue(vr, "arglists").(Seqable); ok && arglists != NIL {
+			for s := arglists.Seq(); !s.IsEmpty(); s = s.Rest() {
+				args := []string{}
+				if vec, ok := s.First().(Seqable); ok {
+					for a := vec.Seq(); !a.IsEmpty(); a = a.Rest() {
+						args = append(args, a.First().ToString(false))
+					}
+				}
+				eldoc = append(eldoc, args)
+			}
+		}
+		res["eldoc"] = eldoc
+		req.reply(res)
+	default:
+		info["status"] = []string{"done"}
+		req.reply(info)
+	}
+}
+
+func metaValue(vr *Var, key string) Object {
+	if meta := vr.GetMeta(); meta != nil {
+		if ok, v := meta.Get(MakeKeyword(key)); ok {
+			return v
+		}
+	}
+	return NIL
+}
+
+// nreplVarInfo returns what the info and lookup ops report about vr.
+func nreplVarInfo(vr *Var) nreplMsg {
+	info := nreplMsg{}
+	if ns, ok := metaValue(vr, "ns").(*Namespace); ok {
+		info["ns"] = ns.Name.ToString(false)
+	}
+	info["name"] = metaValue(vr, "name").ToString(false)
+	if doc, ok := metaValue(vr, "doc").(String); ok {
+		info["doc"] = doc.S
+	}
+	if arglists, ok := metaValue(vr, "arglists").(Seqable); ok && arglists != NIL {
+		lines := []string{}
+		for s := arglists.Seq(); !s.IsEmpty(); s = s.Rest() {
+			lines = append(lines, s.First().ToString(true))
+		}
+		info["arglists-str"] = strings.Join(lines, "\n")
+	}
+	if file, ok := metaValue(vr, "file").(String); ok {
+		info["file"] = file.S
+	}
+	if line, ok := metaValue(vr, "line").(Int); ok {
+		info["line"] = line.I
+	}
+	if column, ok := metaValue(vr, "column").(Int); ok {
+		info["column"] = column.I
+	}
+	if ToBool(metaValue(vr, "macro")) {
+		info["macro"] = "true"
+	}
+	return info
+}
+
+// nreplCompletions returns the vars, namespaces and aliases visible
+// in ns whose names start with prefix.
+func nreplCompletions(ns *Namespace, prefix string) []interface{} {
+	candidates := map[string]string{}
+	if i := strings.Index(prefix, "/"); i > 0 {
+		qualifier, name := prefix[:i], prefix[i+1:]
+		if target := GLOBAL_ENV.NamespaceFor(ns, MakeSymbol(qualifier+"/"+name)); target != nil {
+			for k, vr := range target.Mappings() {
+				if strings.HasPrefix(*k, name) && vr.Namespace() == target {
+					candidates[qualifier+"/"+*k] = nreplVarType(vr)
+				}
+			}
+		}
+	} else {
+		for k, vr := range ns.Mappings() {
+			if strings.HasPrefix(*k, prefix) {
+				candidates[*k] = nreplVarType(vr)
+			}
+		}
+		for k := range GLOBAL_ENV.Namespaces {
+			if strings.HasPrefix(*k, prefix) {
+				candidates[*k] = "namespace"
+			}
+		}
+		for k := range ns.Aliases() {
+			if strings.HasPrefix(*k, prefix) {
+				candidates[*k] = "namespace"
+			}
+		}
+	}
+	names := make([]string, 0, len(candidates))
+	for name := range candidates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	res := make([]interface{}, len(names))
+	for i, name := range names {
+		res[i] = map[string]interface{}{"candidate": name, "type": candidates[name]}
+	}
+	return res
+}
+
+func nreplVarType(vr *Var) string {
+	switch {
+	case ToBool(metaValue(vr, "macro")):
+		return "macro"
+	case metaValue(vr, "arglists") != NIL:
+		return "function"
+	}
+	switch vr.Value.(type) {
+	case *Fn, Proc:
+		return "function"
+	}
+	return "var"
+}
+
+func (req *nreplRequest) get(key string) string {
+	s, _ := req.msg[key].(string)
+	return s
+}
+
+// reply sends a response to req, adding its id and session.
+func (req *nreplRequest) reply(res nreplMsg) {
+	if id, ok := req.msg["id"]; ok {
+		res["id"] = id
+	}
+	if req.session != nil {
+		res["session"] = req.session.id
+	}
+	req.conn.mutex.Lock()
+	defer req.conn.mutex.Unlock()
+	bencodeWrite(req.conn.w, res)
+}
+
+func (req *nreplRequest) replyValue(obj Object) {
+	var b bytes.Buffer
+	PrintObject(obj, &b)
+	req.reply(nreplMsg{"value": b.String(), "ns": GLOBAL_ENV.CurrentNamespace().Name.ToString(false)})
+}
+
+func (w *nreplWriter) Write(p []byte) (int, error) {
+	w.req.reply(nreplMsg{w.stream: string(p)})
+	return len(p), nil
+}
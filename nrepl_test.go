@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bufio"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/candid82/joker/core"
+)
+
+type nreplClient struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func startNrepl(t *testing.T) *nreplClient {
+	client, server := net.Pipe()
+	// Like the main goroutine in nrepl, hold the GIL while setting up.
+	RT.GIL.Lock()
+	s := newNreplServer()
+	RT.GIL.Unlock()
+	go s.serve(server)
+	t.Cleanup(func() { client.Close() })
+	return &nreplClient{t: t, conn: client, r: bufio.NewReader(client)}
+}
+
+func (c *nreplClient) send(msg map[string]interface{}) {
+	c.t.Helper()
+	if err := bencodeWrite(c.conn, msg); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// receive returns the responses to the request with the given id,
+// up to the one with status done.
+func (c *nreplClient) receive(id string) []map[string]interface{} {
+	c.t.Helper()
+	var res []map[string]interface{}
+	c.conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	for {
+		v, err := bencodeRead(c.r)
+		if err != nil {
+			c.t.Fatal(err)
+		}
+		msg := v.(map[string]interface{})
+		if msg["id"] != id {
+			c.t.Fatalf("expected response to %s, got %v", id, msg)
+		}
+		res = append(res, msg)
+		if status, ok := msg["status"].([]interface{}); ok && status[len(status)-1] == "done" {
+			return res
+		}
+	}
+}
+
+func (c *nreplClient) request(msg map[string]interface{}) []map[string]interface{} {
+	c.t.Helper()
+	c.send(msg)
+	return c.receive(msg["id"].(string))
+}
+
+func (c *nreplClient) clone() string {
+	c.t.Helper()
+	return c.request(map[string]interface{}{"op": "clone", "id": "clone"})[0]["new-session"].(string)
+}
+
+// collect joins the values of key in responses.
+func collect(responses []map[string]interface{}, key string) string {
+	var res []string
+	for _, r := range responses {
+		if v, ok := r[key].(string); ok {
+			res = append(res, v)
+		}
+	}
+	return strings.Join(res, "|")
+}
+
+func status(responses []map[string]interface{}) []interface{} {
+	return responses[len(responses)-1]["status"].([]interface{})
+}
+
+func TestNreplEval(t *testing.T) {
+	c := startNrepl(t)
+	session := c.clone()
+	res := c.request(map[string]interface{}{"op": "eval", "id": "1", "session": session,
+		"code": `(print "out") (binding [*out* *err*] (print "err")) (+ 1 2) [*1 *2]`})
+	if got := collect(res, "value"); got != "nil|nil|3|[3 nil]" {
+		t.Errorf("unexpected values %q", got)
+	}
+	if got := collect(res, "out"); got != "out" {
+		t.Errorf("unexpected out %q", got)
+	}
+	if got := collect(res, "err"); got != "err" {
+		t.Errorf("unexpected err %q", got)
+	}
+	if got := collect(res, "ns"); got != "user|user|user|user" {
+		t.Errorf("unexpected ns %q", got)
+	}
+
+	res = c.request(map[string]interface{}{"op": "eval", "id": "2", "session": session, "code": "(/ 1 0) :after"})
+	if got := collect(res, "ex"); got != "EvalError" {
+		t.Errorf("unexpected ex %q", got)
+	}
+	if got := collect(res, "err"); !strings.Contains(got, "Division by zero") {
+		t.Errorf("unexpected err %q", got)
+	}
+	if got := collect(res, "value"); got != ":after" {
+		t.Errorf("unexpected values %q", got)
+	}
+	if got := status(res); !reflect.DeepEqual(got, []interface{}{"eval-error", "done"}) {
+		t.Errorf("unexpected status %v", got)
+	}
+
+	res = c.request(map[string]interface{}{"op": "load-file", "id": "3", "session": session,
+		"file": "(def x 40)\n(+ x 2)", "file-path": "/tmp/x.joke"})
+	if got := collect(res, "value"); got != "42" {
+		t.Errorf("unexpected load-file value %q", got)
+	}
+
+	res = c.request(map[string]interface{}{"op": "eval", "id": "4", "session": session, "code": "x", "ns": "no.such.ns"})
+	if got := status(res); !reflect.DeepEqual(got, []interface{}{"error", "namespace-not-found", "done"}) {
+		t.Errorf("unexpected status %v", got)
+	}
+}
+
+func TestNreplSessions(t *testing.T) {
+	c := startNrepl(t)
+	s1, s2 := c.clone(), c.clone()
+	c.request(map[string]interface{}{"op": "eval", "id": "1", "session": s1, "code": "(ns nrepl.one) :one"})
+	c.request(map[string]interface{}{"op": "eval", "id": "2", "session": s2, "code": ":two"})
+	res := c.request(map[string]interface{}{"op": "eval", "id": "3", "session": s1, "code": "[(str *ns*) *1]"})
+	if got := collect(res, "value"); got != `["nrepl.one" :one]` {
+		t.Errorf("unexpected session 1 state %q", got)
+	}
+	res = c.request(map[string]interface{}{"op": "eval", "id": "4", "session": s2, "code": "[(str *ns*) *1]"})
+	if got := collect(res, "value"); got != `["user" :two]` {
+		t.Errorf("unexpected session 2 state %q", got)
+	}
+	res = c.request(map[string]interface{}{"op": "close", "id": "5", "session": s1})
+	if got := status(res); !reflect.DeepEqual(got, []interface{}{"session-closed", "done"}) {
+		t.Errorf("unexpected status %v", got)
+	}
+	res = c.request(map[string]interface{}{"op": "eval", "id": "6", "session": s1, "code": "1"})
+	if got := status(res); !reflect.DeepEqual(got, []interface{}{"error", "unknown-session", "done"}) {
+		t.Errorf("unexpected status %v", got)
+	}
+}
+
+func TestNreplInterrupt(t *testing.T) {
+	c := startNrepl(t)
+	session := c.clone()
+	res := c.request(map[string]interface{}{"op": "interrupt", "id": "1", "session": session})
+	if got := status(res); !reflect.DeepEqual(got, []interface{}{"session-idle", "done"}) {
+		t.Errorf("unexpected status %v", got)
+	}
+	c.send(map[string]interface{}{"op": "eval", "id": "2", "session": session, "code": "(loop [] (recur)) :not-reached"})
+	time.Sleep(100 * time.Millisecond)
+	c.send(map[string]interface{}{"op": "interrupt", "id": "3", "session": session, "interrupt-id": "2"})
+	var interrupted, done bool
+	for !interrupted || !done {
+		v, err := bencodeRead(c.r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg := v.(map[string]interface{})
+		if msg["value"] != nil {
+			t.Errorf("unexpected value %v", msg["value"])
+		}
+		if s, ok := msg["status"].([]interface{}); ok {
+			switch msg["id"] {
+			case "2":
+				interrupted = reflect.DeepEqual(s, []interface{}{"interrupted", "done"})
+			case "3":
+				done = true
+			}
+		}
+	}
+	res = c.request(map[string]interface{}{"op": "eval", "id": "4", "session": session, "code": ":ok"})
+	if got := collect(res, "value"); got != ":ok" {
+		t.Errorf("unexpected value after interrupt %q", got)
+	}
+}
+
+func TestNreplLookup(t *testing.T) {
+	c := startNrepl(t)
+	c.request(map[string]interface{}{"op": "eval", "id": "1", "code": "(require '[joker.string :as nrepl-str])"})
+
+	res := c.request(map[string]interface{}{"op": "completions", "id": "2", "prefix": "nrepl-str/jo", "ns": "user"})
+	expected := []interface{}{map[string]interface{}{"candidate": "nrepl-str/join", "type": "function"}}
+	if got := res[0]["completions"]; !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected completions %v", got)
+	}
+	res = c.request(map[string]interface{}{"op": "completions", "id": "3", "prefix": "when-l"})
+	expected = []interface{}{map[string]interface{}{"candidate": "when-let", "type": "macro"}}
+	if got := res[0]["completions"]; !reflect.DeepEqual(got, expected) {
+		t.Errorf("unexpected completions %v", got)
+	}
+
+	res = c.request(map[string]interface{}{"op": "info", "id": "4", "sym": "nrepl-str/join", "ns": "user"})
+	if res[0]["ns"] != "joker.string" || res[0]["name"] != "join" || res[0]["arglists-str"] != "[coll]\n[separator coll]" {
+		t.Errorf("unexpected info %v", res[0])
+	}
+	res = c.request(map[string]interface{}{"op": "lookup", "id": "5", "sym": "inc"})
+	if info, ok := res[0]["info"].(map[string]interface{}); !ok || info["ns"] != "joker.core" || info["doc"] == nil {
+		t.Errorf("unexpected lookup %v", res[0])
+	}
+	res = c.request(map[string]interface{}{"op": "eldoc", "id": "6", "sym": "when"})
+	if res[0]["type"] != "macro" || !reflect.DeepEqual(res[0]["eldoc"], []interface{}{[]interface{}{"test", "&", "body"}}) {
+		t.Errorf("unexpected eldoc %v", res[0])
+	}
+	res = c.request(map[string]interface{}{"op": "info", "id": "7", "sym": "no-such-var"})
+	if got := status(res); !reflect.DeepEqual(got, []interface{}{"no-info", "done"}) {
+		t.Errorf("unexpected status %v", got)
+	}
+
+	res = c.request(map[string]interface{}{"op": "describe", "id": "8"})
+	if ops, ok := res[0]["ops"].(map[string]interface{}); !ok || len(ops) != len(nreplOps) {
+		t.Errorf("unexpected describe %v", res[0])
+	}
+	res = c.request(map[string]interface{}{"op": "no-such-op", "id": "9"})
+	if got := status(res); !reflect.DeepEqual(got, []interface{}{"error", "unknown-op", "done"}) {
+		t.Errorf("unexpected status %v", got)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	. "github.com/candid82/joker/core"
+)
+
+// Socket repl (--repl <socket>) and prepl (--prepl <socket>). Each
+// connection is a session with its own namespace, repl values and
+// streams (see replSession). Clients take turns evaluating code,
+// holding the GIL; while waiting for input a client leaves its session
+// and releases the GIL.
+
+// sreplInput reads a client's input, leaving its session and releasing
+// the GIL while blocked, so that other clients can evaluate code meanwhile.
+type sreplInput struct {
+	conn  net.Conn
+	enter func() (leave func())
+	leave func()
+}
+
+func (in *sreplInput) Read(p []byte) (int, error) {
+	in.leave()
+	RT.GIL.Unlock()
+	n, err := in.conn.Read(p)
+	RT.GIL.Lock()
+	in.leave = in.enter()
+	if errors.Is(err, net.ErrClosed) {
+		// Closed by exit called in a goroutine (see serveRepl).
+		err = io.EOF
+	}
+	return n, err
+}
+
+// preplWriter sends what is written to it as {:tag :out} or
+// {:tag :err} messages.
+type preplWriter struct {
+	conn net.Conn
+	tag  Keyword
+}
+
+func (w *preplWriter) Write(p []byte) (int, error) {
+	writePrepl(w.conn, w.tag, String{S: string(p)})
+	return len(p), nil
+}
+
+func writePrepl(w io.Writer, tag Keyword, val Object, kvs ...Object) {
+	m := EmptyArrayMap()
+	m.Add(MakeKeyword("tag"), tag)
+	m.Add(MakeKeyword("val"), val)
+	for i := 0; i < len(kvs); i += 2 {
+		m.Add(kvs[i], kvs[i+1])
+	}
+	fmt.Fprintln(w, m.ToString(true))
+}
+
+func srepl(addr string, phase Phase, prepl bool) {
+	ProcessReplData()
+	GLOBAL_ENV.FindNamespace(MakeSymbol("user")).ReferAll(GLOBAL_ENV.FindNamespace(MakeSymbol("joker.repl")))
+	GLOBAL_ENV.CoreNamespace.Resolve("*repl*").Value = Boolean{B: true}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		fmt.Fprintf(Stderr, "Cannot start srepl listening on %s: %s\n",
+			addr, err.Error())
+		ExitJoker(12)
+	}
+	defer l.Close()
+
+	fmt.Printf("Joker repl listening at %s...\n", l.Addr())
+	replContext := NewReplContext(GLOBAL_ENV)
+	RT.GIL.Unlock()
+	err = acceptRepls(l, phase, replContext, prepl)
+	RT.GIL.Lock()
+	fmt.Fprintf(Stderr, "Cannot start repl accepting on %s: %s\n",
+		l.Addr(), err.Error())
+	EXIT_SESSION = nil
+	ExitJoker(13)
+}
+
+// acceptRepls serves connections to l concurrently until accepting
+// fails. The GIL must not be held.
+func acceptRepls(l net.Listener, phase Phase, replContext *ReplContext, prepl bool) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go serveRepl(conn, phase, replContext, prepl)
+	}
+}
+
+// serveRepl runs a repl session for conn until it's closed.
+func serveRepl(conn net.Conn, phase Phase, sharedContext *ReplContext, prepl bool) {
+	defer conn.Close()
+	RT.GIL.Lock()
+	defer RT.GIL.Unlock()
+
+	fmt.Printf("Joker repl accepting client at %s...\n", conn.RemoteAddr())
+	// The copy has the same *1, *2, *3 and *e vars, but hooks of its own.
+	replContext := *sharedContext
+	session := newReplSession()
+	in := &sreplInput{conn: conn}
+	runeReader := bufio.NewReader(in)
+	if prepl {
+		session.setStdIO(runeReader, &preplWriter{conn: conn, tag: MakeKeyword("out")}, &preplWriter{conn: conn, tag: MakeKeyword("err")})
+	} else {
+		session.setStdIO(runeReader, conn, conn)
+	}
+	in.enter = func() func() {
+		// (exit) ends just this session: it panics with SessionExit,
+		// caught below, or if called by a goroutine started in this
+		// session, closes the connection.
+		EXIT_SESSION = func() { conn.Close() }
+		return session.enter(&replContext)
+	}
+	in.leave = in.enter()
+	defer func() {
+		in.leave()
+	}()
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(SessionExit); !ok {
+				panic(r)
+			}
+		}
+	}()
+
+	parseContext := &ParseContext{GlobalEnv: GLOBAL_ENV}
+	reader := NewReader(runeReader, "<srepl>")
+
+	if !prepl {
+		fmt.Fprintf(Stdout, "Welcome to joker %s, client at %s. Use '(exit)', or close the connection, to exit.\n",
+			VERSION, conn.RemoteAddr())
+		for {
+			fmt.Fprint(Stdout, GLOBAL_ENV.CurrentNamespace().Name.ToString(false)+"=> ")
+			if processReplCommand(reader, phase, parseContext, &replContext) {
+				return
+			}
+		}
+	}
+
+	// Like Clojure's io-prepl: each value (printed with pr-str) or error
+	// is sent as a :ret message with the namespace, the form and the
+	// time it took to evaluate it in milliseconds (unless it couldn't
+	// be read).
+	var form Object
+	var start time.Time
+	ret := func(val Object, kvs ...Object) {
+		kvs = append(kvs, MakeKeyword("ns"), String{S: GLOBAL_ENV.CurrentNamespace().Name.ToString(false)})
+		if form != nil {
+			kvs = append(kvs,
+				MakeKeyword("ms"), MakeInt(int(time.Since(start).Milliseconds())),
+				MakeKeyword("form"), String{S: form.ToString(true)})
+		}
+		writePrepl(conn, MakeKeyword("ret"), val, kvs...)
+	}
+	replContext.onRead = func(obj Object) {
+		form, start = obj, time.Now()
+	}
+	replContext.onValue = func(obj Object) {
+		var b bytes.Buffer
+		PrintObject(obj, &b)
+		ret(String{S: b.String()})
+		form = nil
+	}
+	replContext.onError = func(err error) {
+		ret(String{S: err.Error()}, MakeKeyword("exception"), Boolean{B: true})
+		form = nil
+	}
+	for !processReplCommand(reader, phase, parseContext, &replContext) {
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"net"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/candid82/joker/core"
+)
+
+type sreplClient struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// startSrepl serves repl clients on a local port and returns a function
+// connecting a new client.
+func startSrepl(t *testing.T, prepl bool) func() *sreplClient {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	RT.GIL.Lock()
+	replContext := NewReplContext(GLOBAL_ENV)
+	RT.GIL.Unlock()
+	go acceptRepls(l, EVAL, replContext, prepl)
+	return func() *sreplClient {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return &sreplClient{t: t, conn: conn, r: bufio.NewReader(conn)}
+	}
+}
+
+func (c *sreplClient) send(code string) {
+	c.t.Helper()
+	if _, err := c.conn.Write([]byte(code + "\n")); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// readUntil returns the output up to and including s.
+func (c *sreplClient) readUntil(s string) string {
+	c.t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	var b strings.Builder
+	for !strings.HasSuffix(b.String(), s) {
+		r, _, err := c.r.ReadRune()
+		if err != nil {
+			c.t.Fatalf("%v after reading %q", err, b.String())
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+var msRe = regexp.MustCompile(`:ms \d+`)
+
+// readLine returns the next line of output, with times replaced by 0.
+func (c *sreplClient) readLine() string {
+	c.t.Helper()
+	return msRe.ReplaceAllString(strings.TrimSuffix(c.readUntil("\n"), "\n"), ":ms 0")
+}
+
+func TestSreplSessions(t *testing.T) {
+	connect := startSrepl(t, false)
+	c1, c2 := connect(), connect()
+	c1.readUntil("user=> ")
+	c2.readUntil("user=> ")
+
+	c1.send("(ns srepl.one) :one")
+	if got := c1.readUntil("srepl.one=> "); got != "nil\nsrepl.one=> " {
+		t.Errorf("unexpected output %q", got)
+	}
+	c1.readUntil("srepl.one=> ")
+	// c1 is waiting for input while c2 evaluates code.
+	c2.send(`(println "two") :two`)
+	if got := c2.readUntil("user=> "); got != "two\nnil\nuser=> " {
+		t.Errorf("unexpected output %q", got)
+	}
+	c2.readUntil("user=> ")
+	c1.send("[(str *ns*) *1]")
+	if got := c1.readLine(); got != `["srepl.one" :one]` {
+		t.Errorf("unexpected session 1 state %q", got)
+	}
+	c2.send("[(str *ns*) *1]")
+	if got := c2.readLine(); got != `["user" :two]` {
+		t.Errorf("unexpected session 2 state %q", got)
+	}
+}
+
+func TestSreplExit(t *testing.T) {
+	connect := startSrepl(t, false)
+	c1, c2 := connect(), connect()
+	c1.readUntil("user=> ")
+	c2.readUntil("user=> ")
+
+	c1.send("(exit)")
+	c1.conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	if rest, err := c1.r.ReadString('\n'); err == nil || rest != "" {
+		t.Errorf("expected (exit) to close the connection, got %q, %v", rest, err)
+	}
+	c2.send("(+ 1 2)")
+	if got := c2.readLine(); got != "3" {
+		t.Errorf("unexpected output %q", got)
+	}
+	c3 := connect()
+	c3.readUntil("user=> ")
+	c3.send("(+ 3 4)")
+	if got := c3.readLine(); got != "7" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
+
+func TestSreplExitInFuture(t *testing.T) {
+	connect := startSrepl(t, false)
+	c1, c2, c3 := connect(), connect(), connect()
+	c1.readUntil("user=> ")
+	c2.readUntil("user=> ")
+	c3.readUntil("user=> ")
+
+	c1.send("@(future (exit 1))")
+	c2.send("(future (exit 1))")
+	for _, c := range []*sreplClient{c1, c2} {
+		c.conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+		if _, err := c.r.ReadString(0); err == nil {
+			t.Errorf("expected exit in a future to close the connection")
+		}
+	}
+	c3.send("(+ 1 2)")
+	if got := c3.readLine(); got != "3" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
+
+func TestPrepl(t *testing.T) {
+	c := startSrepl(t, true)()
+	c.send(`(ns prepl.test) (do (print "out") {:a "s"}) (/ 1 0)`)
+	if got := c.readLine(); got != `{:tag :ret, :val "nil", :ns "prepl.test", :ms 0, :form "(ns prepl.test)"}` {
+		t.Errorf("unexpected ret %q", got)
+	}
+	if got := c.readLine(); got != `{:tag :out, :val "out"}` {
+		t.Errorf("unexpected out %q", got)
+	}
+	if got := c.readLine(); got != `{:tag :ret, :val "{:a \"s\"}", :ns "prepl.test", :ms 0, :form "(do (print \"out\") {:a \"s\"})"}` {
+		t.Errorf("unexpected ret %q", got)
+	}
+	if got := c.readLine(); !strings.HasPrefix(got, `{:tag :ret, :val "<joker.core>`) || !strings.Contains(got, "Division by zero") || !strings.Contains(got, ":exception true") {
+		t.Errorf("unexpected exception %q", got)
+	}
+	c.send(")")
+	if got := c.readLine(); got != `{:tag :ret, :val "<srepl>:2:1: Read error: Unmatched delimiter: )", :exception true, :ns "prepl.test"}` {
+		t.Errorf("unexpected read error %q", got)
+	}
+}
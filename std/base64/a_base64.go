@@ -6,6 +6,23 @@ import (
 	. "github.com/candid82/joker/core"
 )
 
+var __decode_bytes__P ProcFn = __decode_bytes_
+var decode_bytes_ Proc = Proc{Fn: __decode_bytes__P, Name: "decode_bytes_", Package: "std/base64"}
+
+func __decode_bytes_(_args []Object) Object {
+	_c := len(_args)
+	switch {
+	case _c == 1:
+		s := ExtractString(_args, 0)
+		_res := decodeBytes(s)
+		return MakeBytes(_res)
+
+	default:
+		PanicArity(_c)
+	}
+	return NIL
+}
+
 var __decode_string__P ProcFn = __decode_string_
 var decode_string_ Proc = Proc{Fn: __decode_string__P, Name: "decode_string_", Package: "std/base64"}
 
@@ -30,7 +47,7 @@ func __encode_string_(_args []Object) Object {
 	_c := len(_args)
 	switch {
 	case _c == 1:
-		s := ExtractString(_args, 0)
+		s := ExtractObject(_args, 0)
 		_res := encodeString(s)
 		return MakeString(_res)
 
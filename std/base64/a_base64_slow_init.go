@@ -12,7 +12,18 @@ func InternsOrThunks() {
 	if VerbosityLevel > 0 {
 		fmt.Fprintln(os.Stderr, "Lazily running slow version of base64.InternsOrThunks().")
 	}
-	base64Namespace.ResetMeta(MakeMeta(nil, `Encodes and decodes strings with standard padded Base64 as specified by RFC 4648.`, "1.0"))
+	base64Namespace.ResetMeta(MakeMeta(nil, `Encodes and decodes strings and Bytes with standard padded Base64 as specified by RFC 4648.`, "1.0"))
+
+	base64Namespace.InternVar("decode-bytes", decode_bytes_,
+		MakeMeta(
+			NewListFrom(NewVectorFrom(MakeSymbol("s").WithMeta(EmptyArrayMap().Assoc(MakeKeyword("tag"), String{S: "String"}).(Map)).(Symbol))),
+			`Decodes standard padded Base64 string s and returns the decoded Bytes.
+
+  Behaves like decode-string but returns Bytes rather than a string.
+
+  Example:
+    (joker.base64/decode-bytes "AP8=")
+    ;; => #bytes "00ff"`, "1.10").Plus(MakeKeyword("tag"), String{S: "Bytes"}))
 
 	base64Namespace.InternVar("decode-string", decode_string_,
 		MakeMeta(
@@ -32,8 +43,9 @@ func InternsOrThunks() {
 
 	base64Namespace.InternVar("encode-string", encode_string_,
 		MakeMeta(
-			NewListFrom(NewVectorFrom(MakeSymbol("s").WithMeta(EmptyArrayMap().Assoc(MakeKeyword("tag"), String{S: "String"}).(Map)).(Symbol))),
-			`Returns the standard padded Base64 encoding of string s.
+			NewListFrom(NewVectorFrom(MakeSymbol("s").WithMeta(EmptyArrayMap().Assoc(MakeKeyword("tag"), String{S: "Object"}).(Map)).(Symbol))),
+			`Returns the standard padded Base64 encoding of s, which may be a string
+  or Bytes.
 
   s is encoded from its raw bytes using the RFC 4648 standard alphabet and = as
   padding when needed.
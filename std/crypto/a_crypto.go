@@ -18,10 +18,10 @@ func __hmac_(_args []Object) Object {
 	switch {
 	case _c == 3:
 		algorithm := ExtractKeyword(_args, 0)
-		message := ExtractString(_args, 1)
-		key := ExtractString(_args, 2)
+		message := ExtractObject(_args, 1)
+		key := ExtractObject(_args, 2)
 		_res := hmacSum(algorithm, message, key)
-		return MakeString(_res)
+		return _res
 
 	default:
 		PanicArity(_c)
@@ -36,10 +36,10 @@ func __md5_(_args []Object) Object {
 	_c := len(_args)
 	switch {
 	case _c == 1:
-		data := ExtractString(_args, 0)
-		t := md5.Sum([]byte(data))
-		_res := string(t[:])
-		return MakeString(_res)
+		data := ExtractObject(_args, 0)
+		t := md5.Sum(ToBytes(data, ""))
+		_res := MakeBinary(data, t[:])
+		return _res
 
 	default:
 		PanicArity(_c)
@@ -54,10 +54,10 @@ func __sha1_(_args []Object) Object {
 	_c := len(_args)
 	switch {
 	case _c == 1:
-		data := ExtractString(_args, 0)
-		t := sha1.Sum([]byte(data))
-		_res := string(t[:])
-		return MakeString(_res)
+		data := ExtractObject(_args, 0)
+		t := sha1.Sum(ToBytes(data, ""))
+		_res := MakeBinary(data, t[:])
+		return _res
 
 	default:
 		PanicArity(_c)
@@ -72,10 +72,10 @@ func __sha224_(_args []Object) Object {
 	_c := len(_args)
 	switch {
 	case _c == 1:
-		data := ExtractString(_args, 0)
-		t := sha256.Sum224([]byte(data))
-		_res := string(t[:])
-		return MakeString(_res)
+		data := ExtractObject(_args, 0)
+		t := sha256.Sum224(ToBytes(data, ""))
+		_res := MakeBinary(data, t[:])
+		return _res
 
 	default:
 		PanicArity(_c)
@@ -90,10 +90,10 @@ func __sha256_(_args []Object) Object {
 	_c := len(_args)
 	switch {
 	case _c == 1:
-		data := ExtractString(_args, 0)
-		t := sha256.Sum256([]byte(data))
-		_res := string(t[:])
-		return MakeString(_res)
+		data := ExtractObject(_args, 0)
+		t := sha256.Sum256(ToBytes(data, ""))
+		_res := MakeBinary(data, t[:])
+		return _res
 
 	default:
 		PanicArity(_c)
@@ -108,10 +108,10 @@ func __sha384_(_args []Object) Object {
 	_c := len(_args)
 	switch {
 	case _c == 1:
-		data := ExtractString(_args, 0)
-		t := sha512.Sum384([]byte(data))
-		_res := string(t[:])
-		return MakeString(_res)
+		data := ExtractObject(_args, 0)
+		t := sha512.Sum384(ToBytes(data, ""))
+		_res := MakeBinary(data, t[:])
+		return _res
 
 	default:
 		PanicArity(_c)
@@ -126,10 +126,10 @@ func __sha512_(_args []Object) Object {
 	_c := len(_args)
 	switch {
 	case _c == 1:
-		data := ExtractString(_args, 0)
-		t := sha512.Sum512([]byte(data))
-		_res := string(t[:])
-		return MakeString(_res)
+		data := ExtractObject(_args, 0)
+		t := sha512.Sum512(ToBytes(data, ""))
+		_res := MakeBinary(data, t[:])
+		return _res
 
 	default:
 		PanicArity(_c)
@@ -144,10 +144,10 @@ func __sha512_224_(_args []Object) Object {
 	_c := len(_args)
 	switch {
 	case _c == 1:
-		data := ExtractString(_args, 0)
-		t := sha512.Sum512_224([]byte(data))
-		_res := string(t[:])
-		return MakeString(_res)
+		data := ExtractObject(_args, 0)
+		t := sha512.Sum512_224(ToBytes(data, ""))
+		_res := MakeBinary(data, t[:])
+		return _res
 
 	default:
 		PanicArity(_c)
@@ -162,10 +162,10 @@ func __sha512_256_(_args []Object) Object {
 	_c := len(_args)
 	switch {
 	case _c == 1:
-		data := ExtractString(_args, 0)
-		t := sha512.Sum512_256([]byte(data))
-		_res := string(t[:])
-		return MakeString(_res)
+		data := ExtractObject(_args, 0)
+		t := sha512.Sum512_256(ToBytes(data, ""))
+		_res := MakeBinary(data, t[:])
+		return _res
 
 	default:
 		PanicArity(_c)
@@ -10,7 +10,7 @@ import (
 	. "github.com/candid82/joker/core"
 )
 
-func hmacSum(algorithm, message, key string) string {
+func hmacSum(algorithm string, message, key Object) Object {
 	var h func() hash.Hash
 	switch algorithm {
 	case ":sha1":
@@ -27,7 +27,7 @@ func hmacSum(algorithm, message, key string) string {
 		panic(RT.NewError("Unsupported algorithm " + algorithm +
 			". Supported algorithms are: :sha1, :sha224, :sha256, :sha384, :sha512"))
 	}
-	mac := hmac.New(h, []byte(key))
-	mac.Write([]byte(message))
-	return string(mac.Sum(nil))
+	mac := hmac.New(h, ToBytes(key, ""))
+	mac.Write(ToBytes(message, ""))
+	return MakeBinary(message, mac.Sum(nil))
 }
@@ -12,7 +12,18 @@ func InternsOrThunks() {
 	if VerbosityLevel > 0 {
 		fmt.Fprintln(os.Stderr, "Lazily running slow version of hex.InternsOrThunks().")
 	}
-	hexNamespace.ResetMeta(MakeMeta(nil, `Encodes and decodes strings with lower-case hexadecimal text.`, "1.0"))
+	hexNamespace.ResetMeta(MakeMeta(nil, `Encodes and decodes strings and Bytes with lower-case hexadecimal text.`, "1.0"))
+
+	hexNamespace.InternVar("decode-bytes", decode_bytes_,
+		MakeMeta(
+			NewListFrom(NewVectorFrom(MakeSymbol("s").WithMeta(EmptyArrayMap().Assoc(MakeKeyword("tag"), String{S: "String"}).(Map)).(Symbol))),
+			`Decodes hexadecimal string s and returns the represented Bytes.
+
+  Throws Error when s has odd length or contains non-hexadecimal characters.
+
+  Example:
+    (joker.hex/decode-bytes "00ff")
+    ;; => #bytes "00ff"`, "1.10").Plus(MakeKeyword("tag"), String{S: "Bytes"}))
 
 	hexNamespace.InternVar("decode-string", decode_string_,
 		MakeMeta(
@@ -28,8 +39,9 @@ func InternsOrThunks() {
 
 	hexNamespace.InternVar("encode-string", encode_string_,
 		MakeMeta(
-			NewListFrom(NewVectorFrom(MakeSymbol("s").WithMeta(EmptyArrayMap().Assoc(MakeKeyword("tag"), String{S: "String"}).(Map)).(Symbol))),
-			`Returns the lower-case hexadecimal encoding of string s.
+			NewListFrom(NewVectorFrom(MakeSymbol("s").WithMeta(EmptyArrayMap().Assoc(MakeKeyword("tag"), String{S: "Object"}).(Map)).(Symbol))),
+			`Returns the lower-case hexadecimal encoding of s, which may be a string
+  or Bytes.
 
   Example:
     (joker.hex/encode-string "hello")
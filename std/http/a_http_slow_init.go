@@ -22,13 +22,14 @@ func InternsOrThunks() {
   request is a map with the following keys:
   - url (string, required)
   - method (string, keyword or symbol, defaults to :get)
-  - body (string)
+  - body (string or Bytes)
   - host (string, overrides the Host header if provided)
   - headers (map from string header names to string values)
+  - as (:bytes to return the response body as Bytes rather than a string)
 
   The response map contains:
   - status (int)
-  - body (string)
+  - body (string, or Bytes when :as is :bytes)
   - headers (map from string header names to vectors of string values)
   - content-length (int; -1 when unknown)
 
@@ -93,7 +94,7 @@ func InternsOrThunks() {
   The full request body is read before handler is called. handler must return a
   response map. For an ordinary response, supported keys are:
   - status (int, optional; omitted uses the HTTP server default)
-  - body (string, optional; defaults to "")
+  - body (string or Bytes, optional; defaults to "")
   - headers (map, optional; values may be strings or seqs of strings)
 
   If handler or response processing throws, the server writes a 500 response
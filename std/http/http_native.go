@@ -1,6 +1,7 @@
 package http
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -42,7 +43,7 @@ func mapToReq(request Map) *http.Request {
 	url := EnsureObjectIsString(getOrPanic(request, MakeKeyword("url"), ":url key must be present in request map"), "url: %s").S
 	var reqBody io.Reader
 	if ok, b := request.Get(MakeKeyword("body")); ok {
-		reqBody = strings.NewReader(EnsureObjectIsString(b, "body: %s").S)
+		reqBody = bytes.NewReader(ToBytes(b, "body: %s"))
 	}
 	req, err := http.NewRequest(method, url, reqBody)
 	PanicOnErr(err)
@@ -82,12 +83,16 @@ func reqToMap(host String, port String, req *http.Request) Map {
 	return res
 }
 
-func respToMap(resp *http.Response) Map {
+func respToMap(resp *http.Response, asBytes bool) Map {
 	defer resp.Body.Close()
 	res := EmptyArrayMap()
 	body, err := ioutil.ReadAll(resp.Body)
 	PanicOnErr(err)
-	res.Add(MakeKeyword("body"), MakeString(string(body)))
+	if asBytes {
+		res.Add(MakeKeyword("body"), MakeBytes(body))
+	} else {
+		res.Add(MakeKeyword("body"), MakeString(string(body)))
+	}
 	res.Add(MakeKeyword("status"), MakeInt(resp.StatusCode))
 	respHeaders := EmptyArrayMap()
 	for k, v := range resp.Header {
@@ -269,9 +274,9 @@ func mapToResp(response Map, w http.ResponseWriter, done <-chan struct{}) {
 		return
 	}
 	status := responseStatus(response)
-	body := ""
+	var body []byte
 	if ok, b := response.Get(MakeKeyword("body")); ok {
-		body = EnsureObjectIsString(b, "HTTP response body: %s").S
+		body = ToBytes(b, "HTTP response body: %s")
 	}
 	if ok, headers := response.Get(MakeKeyword("headers")); ok {
 		addHeaders(headers, w)
@@ -279,7 +284,7 @@ func mapToResp(response Map, w http.ResponseWriter, done <-chan struct{}) {
 	if status != 0 {
 		w.WriteHeader(status)
 	}
-	io.WriteString(w, body)
+	w.Write(body)
 }
 
 func sendRequest(request Map) Map {
@@ -288,7 +293,11 @@ func sendRequest(request Map) Map {
 	resp, err := client.Do(req)
 	RT.GIL.Lock()
 	PanicOnErr(err)
-	return respToMap(resp)
+	asBytes := false
+	if ok, as := request.Get(MakeKeyword("as")); ok {
+		asBytes = as.Equals(MakeKeyword("bytes"))
+	}
+	return respToMap(resp, asBytes)
 }
 
 func startServer(addr string, handler Callable) Object {
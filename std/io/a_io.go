@@ -78,6 +78,42 @@ func __read_(_args []Object) Object {
 	return NIL
 }
 
+var __read_bytes__P ProcFn = __read_bytes_
+var read_bytes_ Proc = Proc{Fn: __read_bytes__P, Name: "read_bytes_", Package: "std/io"}
+
+func __read_bytes_(_args []Object) Object {
+	_c := len(_args)
+	switch {
+	case _c == 2:
+		r := ExtractIOReader(_args, 0)
+		n := ExtractInt(_args, 1)
+		_res := readBytes(r, n)
+		return MakeBytes(_res)
+
+	default:
+		PanicArity(_c)
+	}
+	return NIL
+}
+
+var __write__P ProcFn = __write_
+var write_ Proc = Proc{Fn: __write__P, Name: "write_", Package: "std/io"}
+
+func __write_(_args []Object) Object {
+	_c := len(_args)
+	switch {
+	case _c == 2:
+		w := ExtractIOWriter(_args, 0)
+		data := ExtractObject(_args, 1)
+		_res := write(w, data)
+		return MakeInt(_res)
+
+	default:
+		PanicArity(_c)
+	}
+	return NIL
+}
+
 func Init() {
 
 	InternsOrThunks()
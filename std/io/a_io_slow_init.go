@@ -19,8 +19,9 @@ func InternsOrThunks() {
 			NewListFrom(NewVectorFrom(MakeSymbol("f").WithMeta(EmptyArrayMap().Assoc(MakeKeyword("tag"), String{S: "Object"}).(Map)).(Symbol))),
 			`Closes f and returns nil.
 
-  f must implement close, such as an IOWriter, IOReader, or File. Throws Error
-  when f is not closable or when closing fails.`, "1.0").Plus(MakeKeyword("tag"), String{S: "Nil"}))
+  f must be closable, such as an IOWriter, IOReader, File, BoltDB, POP3Client,
+  or the handle returned by joker.os/watch. Throws Error when f is not
+  closable or when closing fails. See also with-open.`, "1.0").Plus(MakeKeyword("tag"), String{S: "Nil"}))
 
 	ioNamespace.InternVar("copy", copy_,
 		MakeMeta(
@@ -52,4 +53,19 @@ func InternsOrThunks() {
   before EOF, and returns "" when EOF is encountered before any bytes are read.
   Throws Error for non-EOF read failures. n should be non-negative.`, "1.3.6").Plus(MakeKeyword("tag"), String{S: "String"}))
 
+	ioNamespace.InternVar("read-bytes", read_bytes_,
+		MakeMeta(
+			NewListFrom(NewVectorFrom(MakeSymbol("r").WithMeta(EmptyArrayMap().Assoc(MakeKeyword("tag"), String{S: "IOReader"}).(Map)).(Symbol), MakeSymbol("n").WithMeta(EmptyArrayMap().Assoc(MakeKeyword("tag"), String{S: "Int"}).(Map)).(Symbol))),
+			`Performs one read from IOReader r and returns up to n bytes as Bytes.
+
+  Behaves like read but returns Bytes rather than a string, which avoids
+  any assumptions about the encoding of the data.`, "1.10").Plus(MakeKeyword("tag"), String{S: "Bytes"}))
+
+	ioNamespace.InternVar("write", write_,
+		MakeMeta(
+			NewListFrom(NewVectorFrom(MakeSymbol("w").WithMeta(EmptyArrayMap().Assoc(MakeKeyword("tag"), String{S: "IOWriter"}).(Map)).(Symbol), MakeSymbol("data").WithMeta(EmptyArrayMap().Assoc(MakeKeyword("tag"), String{S: "Object"}).(Map)).(Symbol))),
+			`Writes data, which may be a string or Bytes, to IOWriter w.
+
+  Returns the number of bytes written. Throws Error when writing fails.`, "1.10").Plus(MakeKeyword("tag"), String{S: "Int"}))
+
 }
@@ -14,7 +14,7 @@ func pipe() Object {
 }
 
 func close(f Object) Nil {
-	if c, ok := f.(io.Closer); ok {
+	if c, ok := f.(Closer); ok {
 		if err := c.Close(); err != nil {
 			panic(RT.NewError(err.Error()))
 		}
@@ -23,11 +23,21 @@ func close(f Object) Nil {
 	panic(RT.NewError("Object is not closable: " + f.ToString(false)))
 }
 
-func read(r io.Reader, n int) string {
+func readBytes(r io.Reader, n int) []byte {
 	buf := make([]byte, n)
 	cnt, err := r.Read(buf)
 	if err != io.EOF {
 		PanicOnErr(err)
 	}
-	return string(buf[:cnt])
+	return buf[:cnt]
+}
+
+func read(r io.Reader, n int) string {
+	return string(readBytes(r, n))
+}
+
+func write(w io.Writer, data Object) int {
+	n, err := w.Write(ToBytes(data, ""))
+	PanicOnErr(err)
+	return n
 }
@@ -0,0 +1,21 @@
+// This file is generated by generate-std.joke script. Do not edit manually!
+
+//go:build !gen_code
+// +build !gen_code
+
+package json
+
+import (
+	"fmt"
+	. "github.com/candid82/joker/core"
+	"os"
+)
+
+func InternsOrThunks() {
+	if VerbosityLevel > 0 {
+		fmt.Fprintln(os.Stderr, "Lazily running fast version of json.InternsOrThunks().")
+	}
+	STD_thunk_json_json_seq__var = __json_seq_
+	STD_thunk_json_read_string__var = __read_string_
+	STD_thunk_json_write_string__var = __write_string_
+}
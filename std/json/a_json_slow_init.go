@@ -1,5 +1,8 @@
 // This file is generated by generate-std.joke script. Do not edit manually!
 
+//go:build gen_code
+// +build gen_code
+
 package json
 
 import (
@@ -24,7 +27,8 @@ func InternsOrThunks() {
   malformed later input may throw Error only when that element is requested.
 
   opts may contain:
-  - keywords? - truthy to convert JSON object keys from strings to keywords`, "1.0").Plus(MakeKeyword("tag"), String{S: "Seq"}))
+  - keywords? - truthy to convert JSON object keys from strings to keywords
+  - bigdec? - truthy to read numbers with a fraction or exponent as BigDecimal`, "1.0").Plus(MakeKeyword("tag"), String{S: "Seq"}))
 
 	jsonNamespace.InternVar("read-string", read_string_,
 		MakeMeta(
@@ -37,6 +41,8 @@ func InternsOrThunks() {
 
   opts may contain:
   - keywords? - truthy to convert JSON object keys from strings to keywords
+  - bigdec? - truthy to read numbers with a fraction or exponent as BigDecimal
+    (and integers too large for Int as BigInt), preserving their exact value
 
   Example:
     (joker.json/read-string "{\"name\":\"Ada\"}" {:keywords? true})
@@ -48,8 +54,8 @@ func InternsOrThunks() {
 			`Returns the JSON encoding of v.
 
   Keywords are encoded without their leading colon. Map keys are converted to
-  strings, Seqable values become arrays, and numeric values are encoded through
-  their double representation.
+  strings, Seqable values become arrays, BigDecimal values are encoded exactly,
+  and other numeric values are encoded through their double representation.
 
   opts may contain:
   - prefix (string)
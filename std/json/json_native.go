@@ -5,6 +5,7 @@ import (
 	"fmt"
 	. "github.com/candid82/joker/core"
 	"io"
+	"math/big"
 	"strings"
 )
 
@@ -14,6 +15,8 @@ func fromObject(obj Object) interface{} {
 		return obj.ToString(false)[1:]
 	case Boolean:
 		return obj.B
+	case *BigDecimal:
+		return json.Number(obj.Text())
 	case Number:
 		return obj.Double().D
 	case Nil:
@@ -56,6 +59,8 @@ func toObject(v interface{}, keywordize bool) Object {
 			return Int{I: int(v)}
 		}
 		return Double{D: v}
+	case json.Number:
+		return numberToObject(v)
 	case bool:
 		return Boolean{B: v}
 	case nil:
@@ -83,23 +88,57 @@ func toObject(v interface{}, keywordize bool) Object {
 	}
 }
 
-func readString(s string, opts Map) Object {
-	var v interface{}
-	if err := json.Unmarshal([]byte(s), &v); err != nil {
-		panic(RT.NewError("Invalid json: " + err.Error()))
+// numberToObject converts a JSON number read with bigdec? option
+// to Int, BigInt or (for numbers with a fraction or exponent) BigDecimal.
+func numberToObject(n json.Number) Object {
+	s := n.String()
+	if strings.ContainsAny(s, ".eE") {
+		if d, ok := MakeBigDecimalWithOrig(s, ""); ok {
+			return d
+		}
+	} else {
+		if i, err := n.Int64(); err == nil {
+			return Int{I: int(i)}
+		}
+		if b, ok := new(big.Int).SetString(s, 10); ok {
+			return MakeBigInt(b)
+		}
 	}
-	var keywordize bool
+	panic(RT.NewError("Invalid json number: " + s))
+}
+
+func readOpts(opts Map) (keywordize, bigdec bool) {
 	if opts != nil {
 		if ok, v := opts.Get(MakeKeyword("keywords?")); ok {
 			keywordize = ToBool(v)
 		}
+		if ok, v := opts.Get(MakeKeyword("bigdec?")); ok {
+			bigdec = ToBool(v)
+		}
+	}
+	return
+}
+
+func readString(s string, opts Map) Object {
+	var v interface{}
+	keywordize, bigdec := readOpts(opts)
+	if bigdec {
+		dec := json.NewDecoder(strings.NewReader(s))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err != nil {
+			panic(RT.NewError("Invalid json: " + err.Error()))
+		}
+		if _, err := dec.Token(); err != io.EOF {
+			panic(RT.NewError("Invalid json: invalid character after top-level value"))
+		}
+	} else if err := json.Unmarshal([]byte(s), &v); err != nil {
+		panic(RT.NewError("Invalid json: " + err.Error()))
 	}
 	return toObject(v, keywordize)
 }
 
 func jsonSeqOpts(src Object, opts Map) Object {
 	var dec *json.Decoder
-	var keywordize bool
 	var jsonLazySeq func() *LazySeq
 	switch src := src.(type) {
 	case String:
@@ -109,10 +148,9 @@ func jsonSeqOpts(src Object, opts Map) Object {
 	default:
 		panic(RT.NewError("src must be a string or io.Reader"))
 	}
-	if opts != nil {
-		if ok, v := opts.Get(MakeKeyword("keywords?")); ok {
-			keywordize = ToBool(v)
-		}
+	keywordize, bigdec := readOpts(opts)
+	if bigdec {
+		dec.UseNumber()
 	}
 	jsonLazySeq = func() *LazySeq {
 		var c = func(args []Object) Object {
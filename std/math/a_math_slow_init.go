@@ -213,14 +213,15 @@ func InternsOrThunks() {
 			`Returns the precision of a Number.
 
   The precision excludes any sign or exponent. For a BigInt, it's the
-  number of bits needed to represent the number; for a BigFloat, Int,
-  or Double, it's the number of bits available in that instance or
-  type. E.g. (precision 1) returns either 31 or 63, depending on
-  whether the Joker executable is 32-bit or 64-bit (for integers);
-  (precision 1.0) returns 53 (as Double is always a float64); and
-  (precision 1.0M) returns 53 as well, though prepending or appending
-  enough 0 digits will result in a BigFloat with more precision
-  reported.
+  number of bits needed to represent the number; for a BigDecimal,
+  it's the number of decimal digits in its unscaled value; for a
+  BigFloat, Int, or Double, it's the number of bits available in that
+  instance or type. E.g. (precision 1) returns either 31 or 63,
+  depending on whether the Joker executable is 32-bit or 64-bit (for
+  integers); (precision 1.0) returns 53 (as Double is always a
+  float64); (precision (bigfloat "1.0")) returns 53 as well, though
+  prepending or appending enough 0 digits will result in a BigFloat
+  with more precision reported; and (precision 1.10M) returns 3.
 
   If f is not a supported Number type (such as Ratio), a panic
   results.`, "1.0").Plus(MakeKeyword("tag"), String{S: "BigInt"}))
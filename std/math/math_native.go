@@ -21,7 +21,7 @@ func precision(x Number) *big.Int {
 	case Precision:
 		return n.Precision()
 	default:
-		panic(RT.NewArgTypeError(0, x, "BigInt, BigFloat, Int, or Double"))
+		panic(RT.NewArgTypeError(0, x, "BigInt, BigDecimal, BigFloat, Int, or Double"))
 	}
 }
 
@@ -650,6 +650,24 @@ func __ppid_(_args []Object) Object {
 	return NIL
 }
 
+var __read_file__P ProcFn = __read_file_
+var read_file_ Proc = Proc{Fn: __read_file__P, Name: "read_file_", Package: "std/os"}
+
+func __read_file_(_args []Object) Object {
+	_c := len(_args)
+	switch {
+	case _c == 1:
+		name := ExtractString(_args, 0)
+		_res, err := ioutil.ReadFile(name)
+		PanicOnErr(err)
+		return MakeBytes(_res)
+
+	default:
+		PanicArity(_c)
+	}
+	return NIL
+}
+
 var __read_link__P ProcFn = __read_link_
 var read_link_ Proc = Proc{Fn: __read_link__P, Name: "read_link_", Package: "std/os"}
 
@@ -1007,6 +1025,26 @@ func __watch_(_args []Object) Object {
 	return NIL
 }
 
+var __write_file__P ProcFn = __write_file_
+var write_file_ Proc = Proc{Fn: __write_file__P, Name: "write_file_", Package: "std/os"}
+
+func __write_file_(_args []Object) Object {
+	_c := len(_args)
+	switch {
+	case _c == 2:
+		name := ExtractString(_args, 0)
+		data := ExtractObject(_args, 1)
+		err := ioutil.WriteFile(name, ToBytes(data, ""), 0644)
+		PanicOnErr(err)
+		_res := NIL
+		return _res
+
+	default:
+		PanicArity(_c)
+	}
+	return NIL
+}
+
 func Init() {
 	SIGABRT_ = MakeInt(0x6)
 	SIGALRM_ = MakeInt(0xe)
@@ -184,7 +184,8 @@ func InternsOrThunks() {
 	osNamespace.InternVar("exit", exit_,
 		MakeMeta(
 			NewListFrom(NewVectorFrom(MakeSymbol("code").WithMeta(EmptyArrayMap().Assoc(MakeKeyword("tag"), String{S: "Int"}).(Map)).(Symbol)), NewVectorFrom()),
-			`Causes the current program to exit with the given status code (defaults to 0).`, "1.0").Plus(MakeKeyword("tag"), String{S: "Nil"}))
+			`Causes the current program to exit with the given status code (defaults to 0).
+  In a socket repl session, ends just that session.`, "1.0").Plus(MakeKeyword("tag"), String{S: "Nil"}))
 
 	osNamespace.InternVar("expand-env", expand_env_,
 		MakeMeta(
@@ -294,6 +295,11 @@ func InternsOrThunks() {
 			NewListFrom(NewVectorFrom()),
 			`Returns the process id of the caller's parent.`, "1.0").Plus(MakeKeyword("tag"), String{S: "Int"}))
 
+	osNamespace.InternVar("read-file", read_file_,
+		MakeMeta(
+			NewListFrom(NewVectorFrom(MakeSymbol("name").WithMeta(EmptyArrayMap().Assoc(MakeKeyword("tag"), String{S: "String"}).(Map)).(Symbol))),
+			`Reads the named file and returns its contents as Bytes.`, "1.10").Plus(MakeKeyword("tag"), String{S: "Bytes"}))
+
 	osNamespace.InternVar("read-link", read_link_,
 		MakeMeta(
 			NewListFrom(NewVectorFrom(MakeSymbol("name").WithMeta(EmptyArrayMap().Assoc(MakeKeyword("tag"), String{S: "String"}).(Map)).(Symbol))),
@@ -445,7 +451,14 @@ func InternsOrThunks() {
 
   When :recursive? is true, existing child directories are watched and newly
   created child directories are added automatically. Calling the returned
-  cancel function stops the watcher and closes ch. If ch is closed by the
-  caller, the watcher stops sending and shuts itself down.`, "1.7.2").Plus(MakeKeyword("tag"), String{S: "Proc"}))
+  cancel function (or closing it, e.g. via with-open) stops the watcher and
+  closes ch. If ch is closed by the
+  caller, the watcher stops sending and shuts itself down.`, "1.7.2").Plus(MakeKeyword("tag"), String{S: "Callable"}))
+
+	osNamespace.InternVar("write-file", write_file_,
+		MakeMeta(
+			NewListFrom(NewVectorFrom(MakeSymbol("name").WithMeta(EmptyArrayMap().Assoc(MakeKeyword("tag"), String{S: "String"}).(Map)).(Symbol), MakeSymbol("data").WithMeta(EmptyArrayMap().Assoc(MakeKeyword("tag"), String{S: "Object"}).(Map)).(Symbol))),
+			`Writes data, which may be a string or Bytes, to the named file, creating it
+  with mode 0644 (before umask) if necessary and truncating it otherwise.`, "1.10").Plus(MakeKeyword("tag"), String{S: "Nil"}))
 
 }
@@ -4,12 +4,15 @@ import (
 	stdos "os"
 	"path/filepath"
 	"sync"
+	"unsafe"
 
 	"github.com/fsnotify/fsnotify"
 
 	. "github.com/candid82/joker/core"
 )
 
+// fileWatcher is the cancel handle returned by joker.os/watch. It can be
+// called with no arguments or closed (e.g. by with-open) to stop watching.
 type fileWatcher struct {
 	watcher *fsnotify.Watcher
 	ch      *Channel
@@ -21,6 +24,8 @@ type fileWatcher struct {
 	cancelOnce sync.Once
 }
 
+var fileWatcherType *Type
+
 func watch(paths Seqable, ch *Channel, opts Map) Object {
 	recursive := false
 	if ok, obj := opts.Get(MakeKeyword("recursive?")); ok {
@@ -44,15 +49,42 @@ func watch(paths Seqable, ch *Channel, opts Map) Object {
 
 	go fw.run()
 
-	return Proc{
-		Fn: func(args []Object) Object {
-			CheckArity(args, 0, 0)
-			fw.cancel()
-			return NIL
-		},
-		Name:    "watch-cancel",
-		Package: "std/os",
-	}
+	return fw
+}
+
+func (fw *fileWatcher) ToString(escape bool) string {
+	return "#object[FileWatcher]"
+}
+
+func (fw *fileWatcher) Equals(other interface{}) bool {
+	return fw == other
+}
+
+func (fw *fileWatcher) GetInfo() *ObjectInfo {
+	return nil
+}
+
+func (fw *fileWatcher) GetType() *Type {
+	return fileWatcherType
+}
+
+func (fw *fileWatcher) Hash() uint32 {
+	return HashPtr(uintptr(unsafe.Pointer(fw)))
+}
+
+func (fw *fileWatcher) WithInfo(info *ObjectInfo) Object {
+	return fw
+}
+
+func (fw *fileWatcher) Call(args []Object) Object {
+	CheckArity(args, 0, 0)
+	fw.cancel()
+	return NIL
+}
+
+func (fw *fileWatcher) Close() error {
+	fw.cancel()
+	return nil
 }
 
 func (fw *fileWatcher) cancel() {
@@ -195,3 +227,7 @@ func watchOps(op fsnotify.Op) *MapSet {
 	}
 	return ops
 }
+
+func init() {
+	fileWatcherType = RegRefType("FileWatcher", (*fileWatcher)(nil), "Cancel handle returned by joker.os/watch")
+}
@@ -30,7 +30,8 @@ func InternsOrThunks() {
 			`Closes client without sending QUIT and returns nil.
 
   Use close to abandon a session without committing deletion marks. close is
-  idempotent; the POP3Client is unusable afterward.`, "1.8").Plus(MakeKeyword("tag"), String{S: "Nil"}))
+  idempotent; the POP3Client is unusable afterward. with-open closes the
+  client the same way.`, "1.8").Plus(MakeKeyword("tag"), String{S: "Nil"}))
 
 	pop3Namespace.InternVar("connect", connect_,
 		MakeMeta(
@@ -83,6 +83,15 @@ func (client POP3Client) WithInfo(_info *ObjectInfo) Object {
 	return client
 }
 
+// Close closes the connection without sending QUIT, like joker.pop3/close.
+func (client POP3Client) Close() error {
+	RT.GIL.Unlock()
+	defer RT.GIL.Lock()
+	client.client.mu.Lock()
+	defer client.client.mu.Unlock()
+	return client.client.closeLocked()
+}
+
 func EnsureArgIsPOP3Client(args []Object, index int) POP3Client {
 	obj := args[index]
 	if client, ok := obj.(POP3Client); ok {
@@ -14,7 +14,7 @@ func __new_(_args []Object) Object {
 	switch {
 	case _c == 0:
 		_res := new()
-		return MakeString(_res)
+		return MakeUUID(_res)
 
 	default:
 		PanicArity(_c)
@@ -12,14 +12,15 @@ func InternsOrThunks() {
 	if VerbosityLevel > 0 {
 		fmt.Fprintln(os.Stderr, "Lazily running slow version of uuid.InternsOrThunks().")
 	}
-	uuidNamespace.ResetMeta(MakeMeta(nil, `Generates random UUID version 4 strings.`, "1.0"))
+	uuidNamespace.ResetMeta(MakeMeta(nil, `Generates random UUID version 4 values.`, "1.0"))
 
 	uuidNamespace.InternVar("new", new_,
 		MakeMeta(
 			NewListFrom(NewVectorFrom()),
-			`Returns a newly generated random UUID version 4 string.
+			`Returns a newly generated random UUID version 4.
 
-  Uses cryptographic randomness and the canonical 8-4-4-4-12 hexadecimal form.
-  Throws Error if secure randomness cannot be read.`, "1.0").Plus(MakeKeyword("tag"), String{S: "String"}))
+  Uses cryptographic randomness. The result prints as #uuid "..." and
+  (str uuid) returns the canonical 8-4-4-4-12 hexadecimal form.
+  Throws Error if secure randomness cannot be read.`, "1.0").Plus(MakeKeyword("tag"), String{S: "UUID"}))
 
 }
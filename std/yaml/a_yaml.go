@@ -14,7 +14,13 @@ func __read_string_(_args []Object) Object {
 	switch {
 	case _c == 1:
 		s := ExtractString(_args, 0)
-		_res := readString(s)
+		_res := readString(s, nil)
+		return _res
+
+	case _c == 2:
+		s := ExtractString(_args, 0)
+		opts := ExtractMap(_args, 1)
+		_res := readString(s, opts)
 		return _res
 
 	default:
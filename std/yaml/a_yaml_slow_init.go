@@ -16,12 +16,16 @@ func InternsOrThunks() {
 
 	yamlNamespace.InternVar("read-string", read_string_,
 		MakeMeta(
-			NewListFrom(NewVectorFrom(MakeSymbol("s").WithMeta(EmptyArrayMap().Assoc(MakeKeyword("tag"), String{S: "String"}).(Map)).(Symbol))),
+			NewListFrom(NewVectorFrom(MakeSymbol("s").WithMeta(EmptyArrayMap().Assoc(MakeKeyword("tag"), String{S: "String"}).(Map)).(Symbol)), NewVectorFrom(MakeSymbol("s").WithMeta(EmptyArrayMap().Assoc(MakeKeyword("tag"), String{S: "String"}).(Map)).(Symbol), MakeSymbol("opts").WithMeta(EmptyArrayMap().Assoc(MakeKeyword("tag"), String{S: "Map"}).(Map)).(Symbol))),
 			`Parses YAML string s and returns the corresponding Joker value.
 
   YAML mappings become maps, sequences become vectors, scalars become their
   nearest Joker values, and null becomes nil. Throws Error when s is invalid
-  YAML.`, "1.0"))
+  YAML.
+
+  opts may contain:
+  - bigdec? - truthy to read floats as BigDecimal (and integers too large for
+    Int as BigInt), preserving their exact value`, "1.0"))
 
 	yamlNamespace.InternVar("write-string", write_string_,
 		MakeMeta(
@@ -29,7 +33,8 @@ func InternsOrThunks() {
 			`Returns the YAML encoding of v.
 
   Keywords are encoded without their leading colon, map keys are converted to
-  strings, and vectors become YAML sequences. Throws Error when v cannot be
-  encoded.`, "1.0").Plus(MakeKeyword("tag"), String{S: "String"}))
+  strings, and vectors become YAML sequences. BigDecimal and BigInt values
+  are encoded exactly, as numbers with the same digits (and scale). Throws
+  Error when v cannot be encoded.`, "1.0").Plus(MakeKeyword("tag"), String{S: "String"}))
 
 }
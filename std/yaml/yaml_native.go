@@ -2,18 +2,50 @@ package yaml
 
 import (
 	"fmt"
+	"math/big"
+	"math/rand"
+	"regexp"
+	"strconv"
 
 	"gopkg.in/yaml.v2"
 
 	. "github.com/candid82/joker/core"
 )
 
-func fromObject(obj Object) interface{} {
+// exactNumbers holds the exact text of BigDecimal and BigInt values,
+// which yaml.v2 can only encode as (rounded) floats or quoted strings.
+// They are encoded as placeholders that are then replaced by the text.
+type exactNumbers struct {
+	prefix string
+	texts  []string
+}
+
+func (e *exactNumbers) placeholder(text string) string {
+	e.texts = append(e.texts, text)
+	return fmt.Sprintf("%s%d_", e.prefix, len(e.texts)-1)
+}
+
+func (e *exactNumbers) replace(s string) string {
+	if len(e.texts) == 0 {
+		return s
+	}
+	re := regexp.MustCompile(regexp.QuoteMeta(e.prefix) + `(\d+)_`)
+	return re.ReplaceAllStringFunc(s, func(m string) string {
+		i, _ := strconv.Atoi(m[len(e.prefix) : len(m)-1])
+		return e.texts[i]
+	})
+}
+
+func fromObject(obj Object, exact *exactNumbers) interface{} {
 	switch obj := obj.(type) {
 	case Keyword:
 		return obj.ToString(false)[1:]
 	case Boolean:
 		return obj.B
+	case *BigDecimal:
+		return exact.placeholder(obj.Text())
+	case *BigInt:
+		return exact.placeholder(obj.BigInt().String())
 	case Number:
 		return obj.Double().D
 	case Nil:
@@ -22,7 +54,7 @@ func fromObject(obj Object) interface{} {
 		cnt := obj.Count()
 		res := make([]interface{}, cnt)
 		for i := 0; i < cnt; i++ {
-			res[i] = fromObject(obj.Nth(i))
+			res[i] = fromObject(obj.Nth(i), exact)
 		}
 		return res
 	case Map:
@@ -36,7 +68,7 @@ func fromObject(obj Object) interface{} {
 			default:
 				k = p.Key.ToString(false)
 			}
-			res[k] = fromObject(p.Value)
+			res[k] = fromObject(p.Value, exact)
 		}
 		return res
 	default:
@@ -44,14 +76,68 @@ func fromObject(obj Object) interface{} {
 	}
 }
 
+// node holds a decoded YAML value along with the original text
+// of floats, so that they can be read as BigDecimal.
+type node struct {
+	value interface{}
+	text  string
+}
+
+func (n *node) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&n.value); err != nil {
+		return err
+	}
+	switch n.value.(type) {
+	case []interface{}:
+		var items []node
+		if err := unmarshal(&items); err != nil {
+			return err
+		}
+		n.value = items
+	case map[interface{}]interface{}:
+		var m map[interface{}]node
+		if err := unmarshal(&m); err != nil {
+			return err
+		}
+		n.value = m
+	case float64:
+		return unmarshal(&n.text)
+	}
+	return nil
+}
+
 func toObject(v interface{}) Object {
 	switch v := v.(type) {
+	case node:
+		if v.text != "" {
+			if b, ok := new(big.Int).SetString(v.text, 10); ok {
+				return MakeBigInt(b)
+			}
+			if d, ok := MakeBigDecimalWithOrig(v.text, ""); ok {
+				return d
+			}
+		}
+		return toObject(v.value)
+	case []node:
+		res := EmptyVector()
+		for _, v := range v {
+			res = res.Conjoin(toObject(v))
+		}
+		return res
+	case map[interface{}]node:
+		res := EmptyArrayMap()
+		for k, v := range v {
+			res.Add(toObject(k), toObject(v))
+		}
+		return res
 	case string:
 		return MakeString(v)
 	case float64:
 		return Double{D: v}
 	case int:
 		return Int{I: v}
+	case uint64:
+		return MakeBigInt(new(big.Int).SetUint64(v))
 	case bool:
 		return Boolean{B: v}
 	case nil:
@@ -73,18 +159,29 @@ func toObject(v interface{}) Object {
 	}
 }
 
-func readString(s string) Object {
+func readString(s string, opts Map) Object {
 	var v interface{}
-	if err := yaml.Unmarshal([]byte(s), &v); err != nil {
+	var out interface{} = &v
+	if opts != nil {
+		if ok, b := opts.Get(MakeKeyword("bigdec?")); ok && ToBool(b) {
+			n := &node{}
+			v, out = n, n
+		}
+	}
+	if err := yaml.Unmarshal([]byte(s), out); err != nil {
 		panic(RT.NewError("Invalid yaml: " + err.Error()))
 	}
+	if n, ok := v.(*node); ok {
+		return toObject(*n)
+	}
 	return toObject(v)
 }
 
 func writeString(obj Object) string {
-	res, err := yaml.Marshal(fromObject(obj))
+	exact := &exactNumbers{prefix: fmt.Sprintf("joker_exact_%x_", rand.Uint64())}
+	res, err := yaml.Marshal(fromObject(obj, exact))
 	if err != nil {
 		panic(RT.NewError("Cannot encode value to yaml: " + err.Error()))
 	}
-	return string(res)
+	return exact.replace(string(res))
 }
@@ -0,0 +1,4 @@
+(ns deep-and)
+
+(defn f [x]
+  (and x x x x x x x x x x x x x x x x x x x x x x x x x x x x x x))
@@ -0,0 +1,4 @@
+(ns unterminated)
+
+(defn f [x]
+  (let [y 1]
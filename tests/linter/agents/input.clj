@@ -0,0 +1,16 @@
+(ns agents)
+
+(def a (agent 0 :error-mode :continue))
+
+(send a inc)
+(send-off a + 1 2)
+(await a)
+(await-for 100 a)
+(agent-error a)
+(restart-agent a 0 :clear-actions true)
+(set-error-handler! a (fn [ag e] (println ag e)))
+(set-error-mode! a :fail)
+(shutdown-agents)
+(send (atom 0) inc)
+(await-for "1" a)
+(set-error-mode! a "fail")
@@ -0,0 +1,8 @@
+(ns bigdecimal)
+
+(def price 19.99M)
+(decimal? price)
+(bigdec "1.10")
+(with-precision 10 (/ price 3))
+(with-precision 2 :rounding HALF_EVEN (* price 1.5M))
+(bigdec :x)
@@ -0,0 +1,12 @@
+(ns futures)
+
+(def f (future (+ 1 2)))
+(def p (promise))
+
+(deliver p 1)
+(deref p 100 :timeout)
+(future-call 1)
+(future-cancel f)
+(future-done? p)
+(deref f 100)
+(deliver p)
@@ -0,0 +1,17 @@
+(ns ignore-comments.core
+  (:require [clojure.string :as str]))
+
+;; joker:ignore unused-binding
+(let [a 1] "foo")
+
+;; joker:ignore wrong-arity
+(let [b 1] (inc))
+
+#_:joker/ignore
+(defn f
+  [x]
+  (do (let [y 1] x)))
+
+(let [c 1]
+  #_:joker/ignore (do c)
+  c)
@@ -0,0 +1,8 @@
+(ns linters-config.core)
+
+(defn f
+  [x]
+  (do (if x 1)))
+
+(let [a 1]
+  (-> 1))
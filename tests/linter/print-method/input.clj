@@ -0,0 +1,20 @@
+(ns print-method)
+
+(defrecord Point [x y])
+
+(defmethod print-method Point
+  [p ^java.io.Writer w]
+  (.write w (str "#point " [(:x p) (:y p)])))
+
+(defmethod print-dup Point
+  [p w]
+  (print-method p w))
+
+(binding [*print-length* 10
+          *print-level* 3
+          *print-meta* true
+          *print-dup* false
+          *print-namespace-maps* true]
+  (prn (->Point 1 2)))
+
+(print-method (->Point 1 2))
@@ -0,0 +1,11 @@
+(ns sorted)
+
+(defn index [xs]
+  (into (sorted-map) (map-indexed vector xs)))
+
+(conj (sorted-set 3 1 2) 4)
+(sorted-map-by 1 :a 1)
+(subseq (sorted-set 1 2 3) >)
+(rsubseq (sorted-map :a 1) < :b)
+(subseq [1 2 3] > 1)
+(sorted? (sorted-set-by > 1 2))
@@ -0,0 +1,14 @@
+(ns transducers)
+
+(def xf (comp (map inc) (filter odd?) (take 10)))
+
+(defn run [coll]
+  (transduce xf + coll))
+
+(into [] xf (range 100))
+(sequence (partition-all 2) [1 2 3])
+(eduction (map inc) (dedupe) [1 1 2])
+(transduce xf)
+(take-while)
+(vswap! (volatile! 1) inc)
+(completing 1)
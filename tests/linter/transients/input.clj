@@ -0,0 +1,11 @@
+(ns transients)
+
+(defn build [xs]
+  (persistent! (reduce conj! (transient []) xs)))
+
+(transient (list 1 2))
+(conj! (transient []) 1 2)
+(pop! (transient [1]) 1)
+(assoc! (transient {}) :a 1 :b 2)
+(dissoc! (transient {:a 1}) :a :b)
+(disj! (transient #{1}) 1 2)
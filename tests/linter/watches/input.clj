@@ -0,0 +1,12 @@
+(ns watches)
+
+(def config (atom {} :validator map?))
+(def x 1)
+
+(add-watch config :reload (fn [k r old new] (println k r old new)))
+(remove-watch config :reload)
+(set-validator! config nil)
+(get-validator config)
+(alter-var-root #'x inc)
+(add-watch config :k 1)
+(alter-var-root config inc)
@@ -0,0 +1,22 @@
+(ns with-open
+  (:require [clojure.java.io :as io]))
+
+(with-open [r (io/reader "a.txt")
+            w (io/writer "b.txt")]
+  (.write w (slurp r)))
+
+(with-open [r (io/reader "a.txt")]
+  (undefined-fn r))
+
+(with-open [r (io/reader "a.txt") w]
+  r)
+
+(with-open [[a b] (io/reader "a.txt")]
+  a)
+
+(with-open [r (io/reader "a.txt")]
+  (let [x 1]
+    r))
+
+(with-open r
+  r)